@@ -0,0 +1,52 @@
+package multitemplate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// blockSeparator separates a template name from the name of a block defined
+// in it, e.g. "users.html#row"
+const blockSeparator = "#"
+
+// definedTemplate is the part of the html/template and text/template APIs
+// used to address the blocks of a template set
+type definedTemplate[T any] interface {
+	comparable
+	Name() string
+	Lookup(name string) T
+	Templates() []T
+}
+
+// splitBlock splits "name#block" into the template and block names. A name
+// registered as a whole is never split, so names containing the separator
+// keep working.
+func splitBlock(name string, registered func(string) bool) (string, string) {
+	if registered(name) {
+		return name, ""
+	}
+	if i := strings.LastIndex(name, blockSeparator); i >= 0 {
+		return name[:i], name[i+len(blockSeparator):]
+	}
+	return name, ""
+}
+
+// lookupBlock returns the template defined as block in the set of tmpl, or
+// tmpl itself when block is empty. The returned template shares the set of
+// tmpl, so it can call every other template defined there.
+func lookupBlock[T definedTemplate[T]](tmpl T, name, block string) (T, error) {
+	if block == "" {
+		return tmpl, nil
+	}
+	var zero T
+	if t := tmpl.Lookup(block); t != zero {
+		return t, nil
+	}
+
+	var blocks []string
+	for _, t := range tmpl.Templates() {
+		blocks = append(blocks, t.Name())
+	}
+	err := withSuggestions(fmt.Errorf("block %q: %w", block, ErrBlockNotFound), block, blocks)
+	return zero, &TemplateError{Name: name, Err: err}
+}
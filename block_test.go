@@ -0,0 +1,66 @@
+package multitemplate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstanceBlock(t *testing.T) {
+	for name, r := range htmlRenderers(WithBufferedRendering()) {
+		t.Run(name, func(t *testing.T) {
+			r.AddFromFiles("users.html", "tests/blocks/users.html")
+			r.AddFromString("a#b", "whole")
+
+			router := gin.New()
+			router.HTMLRender = r
+			router.GET("/", func(c *gin.Context) {
+				c.HTML(http.StatusOK, c.Query("t"), []string{"tom"})
+			})
+
+			w := performRequestPath(router, "/?t=users.html")
+			assert.Equal(t, "<table>\n<tr><td>tom</td></tr>\n</table>\n\n", w.Body.String())
+
+			w = performRequestPath(router, "/?t=users.html%23row")
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "<tr><td>[tom]</td></tr>", w.Body.String())
+
+			w = performRequestPath(router, "/?t=a%23b")
+			assert.Equal(t, "whole", w.Body.String(), "registered names are never split")
+
+			w = httptest.NewRecorder()
+			err := r.InstanceBlock("users.html", "rows", nil).Render(w)
+			assert.ErrorIs(t, err, ErrBlockNotFound)
+			assert.EqualError(t, err,
+				`multitemplate: template "users.html": block "rows": block not found, did you mean "row"?`)
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+			err = r.InstanceBlock("user.html", "row", nil).Render(httptest.NewRecorder())
+			assert.ErrorIs(t, err, ErrTemplateNotFound)
+
+			out, err := r.RenderString("users.html#row", "jerry")
+			assert.NoError(t, err)
+			assert.Equal(t, "<tr><td>jerry</td></tr>", out)
+			_, err = r.RenderString("users.html#missing", nil)
+			assert.ErrorIs(t, err, ErrBlockNotFound)
+		})
+	}
+}
+
+func TestTextInstanceBlock(t *testing.T) {
+	r := NewText("")
+	r.AddFromString("csv", `{{ range . }}{{ template "row" . }}{{ end }}{{ define "row" }}{{ . }};{{ end }}`)
+
+	w := httptest.NewRecorder()
+	assert.NoError(t, r.Instance("csv#row", "a").Render(w))
+	assert.Equal(t, "a;", w.Body.String())
+
+	out, err := r.RenderString("csv#row", "b")
+	assert.NoError(t, err)
+	assert.Equal(t, "b;", out)
+
+	assert.ErrorIs(t, r.InstanceBlock("csv", "line", nil).Render(httptest.NewRecorder()), ErrBlockNotFound)
+}
@@ -0,0 +1,126 @@
+package multitemplate
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin/render"
+)
+
+// maxPooledBuffer is the capacity above which a buffer is not returned to the
+// pool, so that one very large page does not pin its memory forever
+const maxPooledBuffer = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBuffer {
+		bufferPool.Put(buf)
+	}
+}
+
+// WithBufferedRendering executes templates into a pooled buffer before
+// anything is written to the response. A template that fails halfway then
+// produces a 500 response instead of a truncated page with the status chosen
+// by the handler, see WithErrorTemplate.
+func WithBufferedRendering() Option {
+	return func(r *SyncRender) {
+		r.buffered = true
+	}
+}
+
+// WithErrorTemplate enables buffered rendering and renders the template
+// registered under name, with a 500 status, when a template fails to execute.
+// Its data is the *TemplateError describing the failure. Without an error
+// template a plain 500 response is written, or the error page in dynamic mode.
+func WithErrorTemplate(name string) Option {
+	return func(r *SyncRender) {
+		r.buffered = true
+		r.errorTemplate = name
+	}
+}
+
+// bufferedHTML is a render.Render that only writes the output of a template
+// once it has executed successfully
+type bufferedHTML struct {
+	Template *template.Template
+	Name     string
+	Data     any
+	onError  func(*TemplateError) render.Render
+}
+
+// Render executes the template into a buffer and copies it to w on success
+func (r bufferedHTML) Render(w http.ResponseWriter) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if err := executeBound(buf, w, r.Template, r.Data); err != nil {
+		te := newTemplateError("", "", err)
+		te.Name = r.Name
+		return r.onError(te).Render(w)
+	}
+	r.WriteContentType(w)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// WriteContentType writes the HTML content type
+func (r bufferedHTML) WriteContentType(w http.ResponseWriter) {
+	header := w.Header()
+	if val := header["Content-Type"]; len(val) == 0 {
+		header["Content-Type"] = []string{"text/html; charset=utf-8"}
+	}
+}
+
+// executionError returns the render.Render used when a template failed to
+// execute. The error template is not used for its own failures.
+func (r *SyncRender) executionError(err *TemplateError) render.Render {
+	if r.errorTemplate != "" && r.errorTemplate != err.Name {
+		if tmpl, ok, _ := r.template(r.errorTemplate); ok && tmpl != nil {
+			return errorTemplate{Template: tmpl, Data: err, err: err}
+		}
+	}
+	if r.dynamic {
+		return errorPage{err: err}
+	}
+	return internalError{err: err}
+}
+
+// errorTemplate renders a user supplied template for a failed execution
+type errorTemplate struct {
+	Template *template.Template
+	Data     any
+	err      error
+}
+
+// Render writes the error template with a 500 status and returns the original
+// error so that gin records it. If the error template fails as well, a plain
+// 500 response is written.
+func (e errorTemplate) Render(w http.ResponseWriter) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if err := executeBound(buf, w, e.Template, e.Data); err != nil {
+		return internalError{err: errors.Join(e.err, err)}.Render(w)
+	}
+	bufferedHTML{}.WriteContentType(w)
+	w.WriteHeader(http.StatusInternalServerError)
+	_, _ = buf.WriteTo(w)
+	return e.err
+}
+
+// WriteContentType writes the HTML content type
+func (e errorTemplate) WriteContentType(w http.ResponseWriter) {
+	bufferedHTML{}.WriteContentType(w)
+}
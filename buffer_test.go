@@ -0,0 +1,105 @@
+package multitemplate
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func failingFuncs() Option {
+	return WithFuncs(map[string]any{
+		"fail": func() (string, error) { return "", errors.New("boom") },
+	})
+}
+
+func bufferedRouter(r *SyncRender) *gin.Engine {
+	router := gin.New()
+	router.HTMLRender = r
+	router.GET("/:name", func(c *gin.Context) {
+		c.HTML(http.StatusOK, c.Param("name"), "data")
+	})
+	return router
+}
+
+func TestBufferedRendering(t *testing.T) {
+	r := NewSync(WithBufferedRendering(), failingFuncs())
+	r.AddFromString("ok", "hello {{ . }}")
+	r.AddFromString("broken", "<p>start {{ fail }} end</p>")
+	router := bufferedRouter(r)
+
+	w := performRequestPath(router, "/ok")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hello data", w.Body.String())
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+
+	w = performRequestPath(router, "/broken")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "start", "nothing of the failed page is written")
+	assert.Contains(t, w.Body.String(), "boom")
+}
+
+func TestUnbufferedRenderingTruncates(t *testing.T) {
+	r := NewSync(failingFuncs())
+	r.AddFromString("broken", "<p>start {{ fail }} end</p>")
+
+	w := performRequestPath(bufferedRouter(r), "/broken")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "<p>start ", w.Body.String())
+}
+
+func TestErrorTemplate(t *testing.T) {
+	r := NewSync(WithErrorTemplate("error"), failingFuncs())
+	r.AddFromString("broken", "line one\n{{ fail }}")
+	router := bufferedRouter(r)
+
+	w := performRequestPath(router, "/broken")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "boom", "the error template is not registered yet")
+
+	r.AddFromString("error", `failed {{ .Name }} at line {{ .Line }}`)
+	w = performRequestPath(router, "/broken")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "failed broken at line 2", w.Body.String())
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+
+	r.AddFromString("error", `{{ fail }}`)
+	w = performRequestPath(router, "/broken")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "boom")
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+}
+
+func TestErrorTemplateDynamic(t *testing.T) {
+	r := NewSyncDynamic(WithBufferedRendering(), failingFuncs())
+	r.AddFromString("broken", "{{ fail }}")
+
+	w := performRequestPath(bufferedRouter(r), "/broken")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "<h1>Template error in &#34;broken&#34;</h1>")
+}
+
+func BenchmarkBufferedRendering(b *testing.B) {
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(gin.DebugMode)
+
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"direct", nil},
+		{"buffered", []Option{WithBufferedRendering()}},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			r := NewSync(tc.opts...)
+			r.AddFromFiles("index", "tests/base.html", "tests/article.html")
+			router := bufferedRouter(r)
+			b.ReportAllocs()
+			for b.Loop() {
+				performRequestPath(router, "/index")
+			}
+		})
+	}
+}
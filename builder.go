@@ -0,0 +1,329 @@
+package multitemplate
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path/filepath"
+	"sync"
+)
+
+// Type of dynamic builder
+type builderType int
+
+// Types of dynamic builders
+const (
+	templateType builderType = iota
+	filesTemplateType
+	globTemplateType
+	fsTemplateType
+	stringTemplateType
+)
+
+// Builder for dynamic templates
+type templateBuilder struct {
+	buildType       builderType
+	name            string
+	tmpl            *template.Template
+	templateName    string
+	files           []string
+	glob            string
+	fsys            fs.FS
+	funcMap         template.FuncMap
+	templateStrings []string
+	options         TemplateOptions
+
+	// mu guards the template cached by template() and the files it was built from
+	mu      sync.Mutex
+	cached  *template.Template
+	sources []fileStamp
+}
+
+func newTemplateBuilder(name string, tmpl *template.Template) *templateBuilder {
+	return &templateBuilder{
+		buildType: templateType,
+		name:      name,
+		tmpl:      tmpl,
+		options:   *NewTemplateOptions(),
+	}
+}
+
+func newFilesBuilder(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	files []string,
+) *templateBuilder {
+	return &templateBuilder{
+		buildType: filesTemplateType,
+		name:      name,
+		funcMap:   funcMap,
+		files:     files,
+		options:   options,
+	}
+}
+
+func newGlobBuilder(name string, funcMap template.FuncMap, options TemplateOptions, glob string) *templateBuilder {
+	return &templateBuilder{
+		buildType: globTemplateType,
+		name:      name,
+		funcMap:   funcMap,
+		glob:      glob,
+		options:   options,
+	}
+}
+
+func newFSBuilder(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	fsys fs.FS,
+	files []string,
+) *templateBuilder {
+	return &templateBuilder{
+		buildType: fsTemplateType,
+		name:      name,
+		funcMap:   funcMap,
+		fsys:      fsys,
+		files:     files,
+		options:   options,
+	}
+}
+
+func newStringsBuilder(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	templateStrings []string,
+) *templateBuilder {
+	return &templateBuilder{
+		buildType:       stringTemplateType,
+		name:            name,
+		templateName:    name,
+		funcMap:         funcMap,
+		templateStrings: templateStrings,
+		options:         options,
+	}
+}
+
+// buildTemplate builds the template and panics on failure
+func (tb *templateBuilder) buildTemplate() *template.Template {
+	tmpl, err := tb.build()
+	if err != nil {
+		panic(err)
+	}
+	return tmpl
+}
+
+// template returns the cached template, building it on first use and
+// rebuilding it whenever one of its source files has changed since.
+// When a rebuild fails the last successfully built template is kept and
+// returned along with the error; it is nil if the template never built.
+func (tb *templateBuilder) template() (*template.Template, error) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	if tb.cached != nil && !tb.changed() {
+		return tb.cached, nil
+	}
+	tmpl, sources, err := tb.parse()
+	if err != nil {
+		return tb.cached, tb.wrapError(err)
+	}
+	tb.cached, tb.sources = tmpl, sources
+	return tmpl, nil
+}
+
+// current returns the cached template without checking the source files
+func (tb *templateBuilder) current() *template.Template {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return tb.cached
+}
+
+// swap replaces the cached template with one built elsewhere
+func (tb *templateBuilder) swap(tmpl *template.Template, sources []fileStamp) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	tb.cached, tb.sources = tmpl, sources
+}
+
+// changed reports whether the source files differ from the ones the cached
+// template was built from. Globs are expanded again so that added and
+// removed files are noticed as well.
+func (tb *templateBuilder) changed() bool {
+	files, err := tb.sourceFiles()
+	if err != nil || len(files) != len(tb.sources) {
+		return true
+	}
+	for i := range tb.sources {
+		if tb.sources[i].file != files[i] || tb.sources[i].changed(tb.fsys) {
+			return true
+		}
+	}
+	return false
+}
+
+// build parses the template from its sources. Failures are reported
+// as *TemplateError carrying the registered name of the template.
+func (tb *templateBuilder) build() (*template.Template, error) {
+	tmpl, _, err := tb.parse()
+	if err != nil {
+		return nil, tb.wrapError(err)
+	}
+	return tmpl, nil
+}
+
+func (tb *templateBuilder) wrapError(err error) error {
+	var te *TemplateError
+	if !errors.As(err, &te) {
+		te = &TemplateError{Err: err}
+	}
+	te.Name = tb.name
+	return te
+}
+
+// sourceFiles lists the files the template is built from, expanding globs
+func (tb *templateBuilder) sourceFiles() ([]string, error) {
+	switch tb.buildType {
+	case filesTemplateType:
+		return tb.files, nil
+	case globTemplateType:
+		return globFiles(tb.glob)
+	case fsTemplateType:
+		return globFS(tb.fsys, tb.files)
+	case templateType, stringTemplateType:
+		return nil, nil
+	default:
+		return nil, errors.New("invalid builder type for dynamic template")
+	}
+}
+
+func (tb *templateBuilder) parse() (*template.Template, []fileStamp, error) {
+	files, err := tb.sourceFiles()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkOptions(tb.options.Options); err != nil {
+		return nil, nil, err
+	}
+
+	switch tb.buildType {
+	case templateType:
+		return tb.tmpl.Delims(tb.options.LeftDelimiter, tb.options.RightDelimiter), nil, nil
+	case stringTemplateType:
+		tmpl := tb.newTemplate(tb.templateName)
+		for _, ts := range tb.templateStrings {
+			if _, err := tmpl.Parse(ts); err != nil {
+				return nil, nil, newTemplateError("", ts, err)
+			}
+		}
+		return bindable(tmpl, tb.funcMap), nil, nil
+	default:
+		tmpl, sources, err := parseFiles(tb.newTemplate, tb.fsys, files)
+		if err != nil {
+			return nil, nil, err
+		}
+		return bindable(tmpl, tb.funcMap), sources, nil
+	}
+}
+
+// newTemplate creates the root template with the builder's delimiters,
+// options and functions
+func (tb *templateBuilder) newTemplate(name string) *template.Template {
+	return template.New(name).
+		Delims(tb.options.LeftDelimiter, tb.options.RightDelimiter).
+		Option(tb.options.Options...).
+		Funcs(tb.funcMap)
+}
+
+// checkOptions reports the options template.Option does not know as an
+// error, where it would panic
+func checkOptions(options []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrInvalidOption, r)
+		}
+	}()
+	template.New("").Option(options...)
+	return nil
+}
+
+// globFiles expands the pattern the same way template.ParseGlob does
+func globFiles(pattern string) ([]string, error) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: pattern matches no files: %#q", ErrNoFiles, pattern)
+	}
+	return files, nil
+}
+
+// globFS expands the patterns the same way template.ParseFS does
+func globFS(fsys fs.FS, patterns []string) ([]string, error) {
+	var files []string
+	for _, pattern := range patterns {
+		list, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(list) == 0 {
+			return nil, fmt.Errorf("%w: pattern matches no files: %#q", ErrNoFiles, pattern)
+		}
+		files = append(files, list...)
+	}
+	return files, nil
+}
+
+// parsedTemplate is the part of the html/template and text/template
+// APIs used to parse a template set
+type parsedTemplate[T any] interface {
+	comparable
+	Name() string
+	New(name string) T
+	Parse(text string) (T, error)
+}
+
+// parseFiles mirrors template.ParseFiles and template.ParseFS: the root template
+// is named after the first file and every file becomes an associated template
+// named after its base name. Unlike the standard library it reports which file
+// failed and where.
+func parseFiles[T parsedTemplate[T]](
+	newTemplate func(name string) T,
+	fsys fs.FS,
+	files []string,
+) (T, []fileStamp, error) {
+	var tmpl T
+	if len(files) == 0 {
+		return tmpl, nil, ErrNoFiles
+	}
+
+	var zero T
+	stamps := make([]fileStamp, 0, len(files))
+	for _, file := range files {
+		b, name, stamp, err := readSource(fsys, file)
+		if err != nil {
+			return zero, nil, &TemplateError{File: file, Err: err}
+		}
+		stamps = append(stamps, stamp)
+
+		var t T
+		switch {
+		case tmpl == zero:
+			tmpl = newTemplate(name)
+			t = tmpl
+		case name == tmpl.Name():
+			t = tmpl
+		default:
+			t = tmpl.New(name)
+		}
+		if _, err := t.Parse(string(b)); err != nil {
+			return zero, nil, newTemplateError(file, string(b), err)
+		}
+	}
+	return tmpl, stamps, nil
+}
@@ -0,0 +1,421 @@
+package multitemplate
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"text/template/parse"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// translateFunc is the name of the template function of a Catalog
+const translateFunc = "T"
+
+// countArg is the placeholder selecting the plural form of a message
+const countArg = "count"
+
+// pluralForms are the CLDR plural categories a message may define
+var pluralForms = []string{"zero", "one", "two", "few", "many", "other"}
+
+// catalogDecoders decode the catalog files by extension
+var catalogDecoders = map[string]binding.BindingBody{
+	".json": binding.JSON,
+	".yaml": binding.YAML,
+	".yml":  binding.YAML,
+}
+
+// PluralFunc returns the plural category, such as "one" or "other", of the
+// count n in locale
+type PluralFunc func(locale string, n float64) string
+
+// CatalogConfig configures a Catalog
+type CatalogConfig struct {
+	// Default is the locale tried after the locales of the request, e.g. "en"
+	Default string
+	// Plural selects the plural form of messages, "one" for 1 and "other"
+	// otherwise when nil. A "zero" form is always used for 0 when defined.
+	Plural PluralFunc
+	// Reload reloads the catalog files when they change, checked once per
+	// request. Set it to gin.IsDebugging() to reload along with the
+	// DynamicRender created by NewRenderer.
+	Reload bool
+}
+
+// message is a translated message, with plural forms or not
+type message struct {
+	text   string
+	plural map[string]string
+}
+
+// Catalog holds the translated messages of every locale and provides the T
+// template function:
+//
+//	{{ T "cart.title" }}
+//	{{ T "cart.items" "count" .Count }}
+//	{{ T "greeting" "name" .User.Name }}
+//
+// Arguments are pairs of placeholder names and values, or a single map.
+// Placeholders are written {name} in messages. A message may be a map of
+// plural forms, selected by the "count" argument:
+//
+//	{"cart": {"items": {"zero": "Your cart is empty", "one": "{count} item", "other": "{count} items"}}}
+type Catalog struct {
+	fsys   fs.FS
+	config CatalogConfig
+
+	mu       sync.RWMutex
+	messages map[string]map[string]message // locale -> key -> message
+	sources  []fileStamp
+	missing  sync.Map // locales + "\x00" + key -> struct{}, logged in debug mode
+}
+
+// LoadCatalog loads the catalog files at the root of fsys. Each file holds the
+// messages of the locale named after it, e.g. en.json or de-AT.yaml; nested
+// objects are flattened into keys joined by dots.
+func LoadCatalog(fsys fs.FS, config CatalogConfig) (*Catalog, error) {
+	c := &Catalog{fsys: fsys, config: config}
+	messages, sources, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	c.messages, c.sources = messages, sources
+	return c, nil
+}
+
+// Locales returns the locales of the catalog, sorted
+func (c *Catalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Sorted(maps.Keys(c.messages))
+}
+
+// Has reports whether locale defines the message key
+func (c *Catalog) Has(locale, key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.messages[locale][key]
+	return ok
+}
+
+// Translate returns the message key of the first of locales, then of the
+// default locale, that defines it, with its placeholders replaced by args. An
+// undefined key is returned as is, and logged in debug mode.
+func (c *Catalog) Translate(locales []string, key string, args ...any) (string, error) {
+	values, err := messageArgs(args)
+	if err != nil {
+		return "", fmt.Errorf("%s %q: %w", translateFunc, key, err)
+	}
+	if c.config.Default != "" {
+		locales = append(slices.Clip(locales), c.config.Default)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, locale := range locales {
+		if msg, ok := c.messages[locale][key]; ok {
+			return msg.format(locale, values, c.config.Plural)
+		}
+	}
+	c.logMissing(locales, key)
+	return key, nil
+}
+
+// RequestFuncs returns the T function translating into the locale selected by
+// the Localize middleware, to use with BindRequestFuncs
+func (c *Catalog) RequestFuncs() RequestFuncMap {
+	return RequestFuncMap{
+		translateFunc: func(ctx *gin.Context) any {
+			c.reload()
+			locales := ctx.GetStringSlice(localeKey)
+			return func(key string, args ...any) (string, error) {
+				return c.Translate(locales, key, args...)
+			}
+		},
+	}
+}
+
+// logMissing logs a key undefined in every locale once, in debug mode
+func (c *Catalog) logMissing(locales []string, key string) {
+	if !gin.IsDebugging() {
+		return
+	}
+	if _, logged := c.missing.LoadOrStore(strings.Join(locales, ",")+"\x00"+key, struct{}{}); logged {
+		return
+	}
+	fmt.Fprintf(gin.DefaultWriter, "[GIN-debug] [WARNING] multitemplate: message %q is missing in %v\n", key, locales)
+}
+
+// reload loads the catalog files again when Reload is set and they changed.
+// The messages loaded last are kept when the files fail to load.
+func (c *Catalog) reload() {
+	if !c.config.Reload || !c.changed() {
+		return
+	}
+	messages, sources, err := c.load()
+	if err != nil {
+		if gin.IsDebugging() {
+			fmt.Fprintf(gin.DefaultWriter, "[GIN-debug] [WARNING] multitemplate: catalog: %v\n", err)
+		}
+		return
+	}
+	c.mu.Lock()
+	c.messages, c.sources = messages, sources
+	c.missing.Clear()
+	c.mu.Unlock()
+}
+
+// changed reports whether the catalog files differ from the ones loaded
+func (c *Catalog) changed() bool {
+	files, err := c.files()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil || len(files) != len(c.sources) {
+		return true
+	}
+	for i := range c.sources {
+		if c.sources[i].file != files[i] || c.sources[i].changed(c.fsys) {
+			return true
+		}
+	}
+	return false
+}
+
+// files lists the catalog files of fsys, sorted
+func (c *Catalog) files() ([]string, error) {
+	entries, err := fs.ReadDir(c.fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if _, ok := catalogDecoders[path.Ext(e.Name())]; ok && !e.IsDir() {
+			files = append(files, e.Name())
+		}
+	}
+	return files, nil
+}
+
+// load reads and decodes every catalog file
+func (c *Catalog) load() (map[string]map[string]message, []fileStamp, error) {
+	files, err := c.files()
+	if err != nil {
+		return nil, nil, err
+	}
+	messages := make(map[string]map[string]message)
+	sources := make([]fileStamp, 0, len(files))
+	var errs []error
+	for _, file := range files {
+		b, _, stamp, err := readSource(c.fsys, file)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sources = append(sources, stamp)
+
+		var raw map[string]any
+		if err := catalogDecoders[path.Ext(file)].BindBody(b, &raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+		locale := strings.TrimSuffix(file, path.Ext(file))
+		if messages[locale] == nil {
+			messages[locale] = make(map[string]message)
+		}
+		if err := flattenMessages("", raw, messages[locale]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, nil, err
+	}
+	return messages, sources, nil
+}
+
+// flattenMessages adds the messages of raw to messages, with their keys
+// prefixed by prefix
+func flattenMessages(prefix string, raw map[string]any, messages map[string]message) error {
+	for key, value := range raw {
+		key = prefix + key
+		switch v := value.(type) {
+		case map[string]any:
+			if plural, ok := pluralMessage(v); ok {
+				messages[key] = message{plural: plural}
+				continue
+			}
+			if err := flattenMessages(key+".", v, messages); err != nil {
+				return err
+			}
+		case []any:
+			return fmt.Errorf("message %q: lists are not supported", key)
+		default:
+			messages[key] = message{text: fmt.Sprint(v)}
+		}
+	}
+	return nil
+}
+
+// pluralMessage returns the forms of raw when it defines the plural forms of a
+// message: an "other" form and no key that is not a plural category
+func pluralMessage(raw map[string]any) (map[string]string, bool) {
+	if _, ok := raw["other"]; !ok {
+		return nil, false
+	}
+	forms := make(map[string]string, len(raw))
+	for form, value := range raw {
+		text, ok := value.(string)
+		if !ok || !slices.Contains(pluralForms, form) {
+			return nil, false
+		}
+		forms[form] = text
+	}
+	return forms, true
+}
+
+// messageArgs converts the arguments of T into placeholder values
+func messageArgs(args []any) (map[string]any, error) {
+	if len(args) == 1 {
+		switch v := args[0].(type) {
+		case gin.H:
+			return v, nil
+		case map[string]any:
+			return v, nil
+		}
+	}
+	if len(args)%2 != 0 {
+		return nil, errors.New("arguments must be pairs of names and values, or a map")
+	}
+	values := make(map[string]any, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		name, ok := args[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("argument name %v is not a string", args[i])
+		}
+		values[name] = args[i+1]
+	}
+	return values, nil
+}
+
+// format returns the text of the message for values
+func (m message) format(locale string, values map[string]any, plural PluralFunc) (string, error) {
+	text := m.text
+	if m.plural != nil {
+		n, ok := number(values[countArg])
+		if !ok {
+			return "", fmt.Errorf("plural message needs a numeric %q argument", countArg)
+		}
+		if plural == nil {
+			plural = defaultPlural
+		}
+		form := plural(locale, n)
+		if _, ok := m.plural["zero"]; ok && n == 0 {
+			form = "zero"
+		}
+		if text, ok = m.plural[form]; !ok {
+			text = m.plural["other"]
+		}
+	}
+	if len(values) == 0 || !strings.Contains(text, "{") {
+		return text, nil
+	}
+
+	pairs := make([]string, 0, 2*len(values))
+	for name, value := range values {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(text), nil
+}
+
+// defaultPlural is the plural rule of English and many other languages
+func defaultPlural(_ string, n float64) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// number converts a numeric value to float64
+func number(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.CanInt():
+		return float64(rv.Int()), true
+	case rv.CanUint():
+		return float64(rv.Uint()), true
+	case rv.CanFloat():
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// Lint reports the {{ T "key" }} calls, with a constant key, of the template
+// sets composed from fsys like LoadFS whose key is not defined in every
+// locale of the catalog. Parse errors are left to the Lint function.
+func (c *Catalog) Lint(fsys fs.FS, configs ...LoadConfig) ([]LintIssue, error) {
+	sets, err := planDirectory(fsys, configs)
+	if err != nil {
+		return nil, err
+	}
+	locales := c.Locales()
+
+	l := &linter{fsys: fsys, files: make(map[lintKey]*lintFile)}
+	seen := make(map[*lintFile]bool)
+	for _, set := range sets {
+		for _, file := range set.files {
+			f, err := l.parse(file, set.config.Options)
+			if err != nil {
+				return nil, err
+			}
+			if f.trees == nil || seen[f] {
+				continue
+			}
+			seen[f] = true
+			for _, name := range slices.Sorted(maps.Keys(f.trees)) {
+				walkCommands(f.trees[name].Root, func(cmd *parse.CommandNode) {
+					key, ok := translateKey(cmd)
+					if !ok {
+						return
+					}
+					var missing []string
+					for _, locale := range locales {
+						if !c.Has(locale, key) {
+							missing = append(missing, locale)
+						}
+					}
+					switch {
+					case len(missing) == 0:
+					case len(missing) == len(locales):
+						l.report(SeverityError, "", f, cmd.Position(), "message %q is not defined", key)
+					default:
+						l.report(SeverityError, "", f, cmd.Position(),
+							"message %q is missing in %s", key, strings.Join(missing, ", "))
+					}
+				})
+			}
+		}
+	}
+	return l.issues, nil
+}
+
+// translateKey returns the key of a {{ T "key" }} call
+func translateKey(cmd *parse.CommandNode) (string, bool) {
+	if len(cmd.Args) < 2 {
+		return "", false
+	}
+	if ident, ok := cmd.Args[0].(*parse.IdentifierNode); !ok || ident.Ident != translateFunc {
+		return "", false
+	}
+	key, ok := cmd.Args[1].(*parse.StringNode)
+	if !ok {
+		return "", false
+	}
+	return key.Text, true
+}
@@ -0,0 +1,104 @@
+package multitemplate
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogTranslate(t *testing.T) {
+	c, err := LoadCatalog(os.DirFS("tests/i18n/locales"), CatalogConfig{Default: "en"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"de", "en"}, c.Locales())
+
+	for _, tc := range []struct {
+		locales  []string
+		key      string
+		args     []any
+		expected string
+	}{
+		{[]string{"de"}, "title", nil, "Laden"},
+		{[]string{"fr"}, "title", nil, "Shop"},
+		{[]string{"de"}, "greeting", []any{"name", "Ada"}, "Hello Ada"},
+		{nil, "greeting", []any{gin.H{"name": "Ada"}}, "Hello Ada"},
+		{nil, "cart.items", []any{"count", 0}, "Your cart is empty"},
+		{nil, "cart.items", []any{"count", 1}, "1 item"},
+		{nil, "cart.items", []any{"count", uint8(3)}, "3 items"},
+		{[]string{"de"}, "cart.items", []any{"count", 0}, "0 Artikel"},
+		{[]string{"de"}, "unknown", nil, "unknown"},
+	} {
+		s, err := c.Translate(tc.locales, tc.key, tc.args...)
+		assert.NoError(t, err, tc.key)
+		assert.Equal(t, tc.expected, s, tc.key)
+	}
+
+	_, err = c.Translate(nil, "greeting", "name")
+	assert.ErrorContains(t, err, "pairs")
+	_, err = c.Translate(nil, "cart.items")
+	assert.ErrorContains(t, err, `numeric "count"`)
+
+	_, err = LoadCatalog(os.DirFS("tests/i18n/templates"), CatalogConfig{})
+	assert.NoError(t, err, "directories without catalog files are empty catalogs")
+}
+
+func TestCatalogRequestFuncs(t *testing.T) {
+	c, err := LoadCatalog(os.DirFS("tests/i18n/locales"), CatalogConfig{Default: "en"})
+	assert.NoError(t, err)
+	funcs := c.RequestFuncs()
+
+	r := NewDynamic()
+	r.AddFromFilesFuncs("page", funcs.FuncMap(), "tests/i18n/templates/page.html")
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.Use(Localize(LocaleConfig{Locales: c.Locales(), Default: "en"}), BindRequestFuncs(funcs))
+	router.GET("/", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "page", gin.H{"Name": "Ada", "Count": 2})
+	})
+
+	w := performRequestHeader(router, "/", http.Header{"Accept-Language": {"de-DE"}})
+	assert.Equal(t, "<h1>Laden</h1><p>Hello Ada</p><p>2 Artikel</p>\n", w.Body.String())
+	w = performRequest(router)
+	assert.Equal(t, "<h1>Shop</h1><p>Hello Ada</p><p>2 items</p>\n", w.Body.String())
+}
+
+func TestCatalogReload(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "en.json")
+	assert.NoError(t, os.WriteFile(file, []byte(`{"title": "Shop"}`), 0o600))
+
+	c, err := LoadCatalog(os.DirFS(dir), CatalogConfig{Default: "en", Reload: true})
+	assert.NoError(t, err)
+	translate := func() string {
+		ctx, _ := gin.CreateTestContext(nil)
+		s, err := c.RequestFuncs()[translateFunc](ctx).(func(string, ...any) (string, error))("title")
+		assert.NoError(t, err)
+		return s
+	}
+	assert.Equal(t, "Shop", translate())
+
+	assert.NoError(t, os.WriteFile(file, []byte(`{"title": "Store"}`), 0o600))
+	assert.NoError(t, os.Chtimes(file, time.Now().Add(time.Second), time.Now().Add(time.Second)))
+	assert.Equal(t, "Store", translate())
+
+	assert.NoError(t, os.WriteFile(file, []byte(`{"title": `), 0o600))
+	assert.NoError(t, os.Chtimes(file, time.Now().Add(2*time.Second), time.Now().Add(2*time.Second)))
+	assert.Equal(t, "Store", translate(), "a broken catalog keeps the last messages")
+}
+
+func TestCatalogLint(t *testing.T) {
+	c, err := LoadCatalog(os.DirFS("tests/i18n/locales"), CatalogConfig{Default: "en"})
+	assert.NoError(t, err)
+
+	issues, err := c.Lint(os.DirFS("tests/i18n/templates"), LoadConfig{Pages: "."})
+	assert.NoError(t, err)
+	assert.Equal(t, []LintIssue{
+		{Severity: SeverityError, File: "missing.html", Line: 1, Column: 18, Message: `message "farewell" is not defined`},
+		{Severity: SeverityError, File: "page.html", Line: 1, Column: 31, Message: `message "greeting" is missing in de`},
+	}, issues)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-contrib/multitemplate"
+)
+
+// config is the content of the config file, e.g.
+//
+//	{
+//	  "root": "templates",
+//	  "funcs": ["upper", "formatDate"],
+//	  "sets": [
+//	    {"layouts": "layouts/*.html", "partials": "partials/*.html", "pages": "pages", "extensions": [".html"]},
+//	    {"layouts": "mail/layout.txt", "pages": "mail", "leftDelimiter": "[[", "rightDelimiter": "]]"}
+//	  ],
+//	  "types": {"article.html": "ArticleData"},
+//	  "catalog": "locales"
+//	}
+//
+// Each set is a multitemplate.LoadConfig. Funcs lists the names of the
+// template functions of the application: they are declared with stubs so that
+// the templates parse. Root is relative to the config file. Types maps
+// template names to the Go type of their data, for the typed helpers of the
+// generate command. Catalog is the directory of the message catalogs the T
+// calls of the lint command are checked against, relative to the config file.
+type config struct {
+	Root    string            `json:"root"`
+	Funcs   []string          `json:"funcs"`
+	Sets    []setConfig       `json:"sets"`
+	Types   map[string]string `json:"types"`
+	Catalog string            `json:"catalog"`
+}
+
+// setConfig is the JSON form of a multitemplate.LoadConfig
+type setConfig struct {
+	Layouts        string   `json:"layouts"`
+	Partials       string   `json:"partials"`
+	Pages          string   `json:"pages"`
+	Extensions     []string `json:"extensions"`
+	LeftDelimiter  string   `json:"leftDelimiter"`
+	RightDelimiter string   `json:"rightDelimiter"`
+	Funcs          []string `json:"funcs"`
+}
+
+// defaultConfig treats every file under the root as a page on its own
+var defaultConfig = config{Root: ".", Sets: []setConfig{{Pages: "."}}}
+
+// loadConfig reads the config file, or returns the default config when file is empty
+func loadConfig(file string) (config, error) {
+	if file == "" {
+		return defaultConfig, nil
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return config{}, err
+	}
+	var cfg config
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return config{}, err
+	}
+	cfg.Root = filepath.Join(filepath.Dir(file), cfg.Root)
+	if cfg.Catalog != "" {
+		cfg.Catalog = filepath.Join(filepath.Dir(file), cfg.Catalog)
+	}
+	if len(cfg.Sets) == 0 {
+		cfg.Sets = defaultConfig.Sets
+	}
+	return cfg, nil
+}
+
+// loadConfigs converts the sets to the configs of LoadDirectory
+func (c config) loadConfigs() []multitemplate.LoadConfig {
+	configs := make([]multitemplate.LoadConfig, len(c.Sets))
+	for i, set := range c.Sets {
+		options := multitemplate.NewTemplateOptions()
+		if set.LeftDelimiter != "" {
+			options.LeftDelimiter = set.LeftDelimiter
+		}
+		if set.RightDelimiter != "" {
+			options.RightDelimiter = set.RightDelimiter
+		}
+		configs[i] = multitemplate.LoadConfig{
+			Layouts:    set.Layouts,
+			Partials:   set.Partials,
+			Pages:      set.Pages,
+			Extensions: set.Extensions,
+			Funcs:      stubFuncs(c.Funcs, set.Funcs),
+			Options:    *options,
+		}
+	}
+	return configs
+}
+
+// stubFuncs declares the named functions with a stub accepting any arguments
+func stubFuncs(lists ...[]string) map[string]any {
+	funcs := make(map[string]any)
+	for _, names := range lists {
+		for _, name := range names {
+			funcs[name] = func(...any) any { return nil }
+		}
+	}
+	return funcs
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-contrib/multitemplate"
+)
+
+var generatedTemplate = template.Must(template.New("generated").Parse(
+	`// Code generated by multitemplate generate; DO NOT EDIT.
+
+package {{ .Package }}
+{{ if .Helpers }}
+import "github.com/gin-gonic/gin"
+{{ end }}
+// Names of the templates registered by LoadDirectory
+const (
+{{- range .Constants }}
+	{{ .Ident }} = {{ printf "%q" .Name }}
+{{- end }}
+)
+{{ range .Helpers }}
+// {{ .Func }} renders the {{ printf "%q" .Name }} template
+func {{ .Func }}(c *gin.Context, code int, data {{ .Type }}) {
+	c.HTML(code, {{ .Const }}, data)
+}
+{{ end }}`,
+))
+
+// generated is the data of generatedTemplate
+type generated struct {
+	Package   string
+	Constants []constant
+	Helpers   []helper
+}
+
+type constant struct {
+	Ident string
+	Name  string
+}
+
+type helper struct {
+	Func  string
+	Const string
+	Name  string
+	Type  string
+}
+
+// runGenerate implements the generate command
+func runGenerate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configFile := flags.String("config", "", "config file describing the template sets")
+	output := flags.String("o", "templates_gen.go", "output file, - for standard output")
+	pkg := flags.String("package", os.Getenv("GOPACKAGE"), "package of the generated file")
+	prefix := flags.String("prefix", defaultPrefix, "prefix of the name constants")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 1 {
+		return exitUsage
+	}
+	if *pkg == "" {
+		fmt.Fprintln(stderr, "multitemplate: -package is required outside of go generate")
+		return exitUsage
+	}
+
+	cfg, err := loadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "multitemplate: %v\n", err)
+		return exitUsage
+	}
+	if flags.NArg() == 1 {
+		cfg.Root = flags.Arg(0)
+	}
+
+	r := multitemplate.New()
+	if err := r.LoadFS(os.DirFS(cfg.Root), cfg.loadConfigs()...); err != nil {
+		fmt.Fprintf(stderr, "multitemplate: %v\n", err)
+		return exitIssues
+	}
+	src, err := generate(*pkg, *prefix, r.Names(), cfg.Types)
+	if err != nil {
+		fmt.Fprintf(stderr, "multitemplate: %v\n", err)
+		return exitIssues
+	}
+
+	if *output == "-" {
+		_, _ = stdout.Write(src)
+		return exitOK
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil { //nolint:gosec // generated source is not secret
+		fmt.Fprintf(stderr, "multitemplate: %v\n", err)
+		return exitIssues
+	}
+	return exitOK
+}
+
+// defaultPrefix is the prefix of the name constants, also given to the
+// constants that would not start with a letter
+const defaultPrefix = "Template"
+
+// generate returns the formatted source declaring a constant for every name
+// and a helper for every template with a type. Names whose identifiers still
+// collide once their extension is kept are an error.
+func generate(pkg, prefix string, names []string, types map[string]string) ([]byte, error) {
+	idents := identifiers(names)
+	declared := make(map[string]string) // identifier -> template name
+	declare := func(ident, name string) error {
+		if other, ok := declared[ident]; ok && other == name {
+			return fmt.Errorf("template %q: constant and helper have the same identifier %s, change -prefix", name, ident)
+		} else if ok {
+			return fmt.Errorf("templates %q and %q have the same identifier %s", other, name, ident)
+		}
+		declared[ident] = name
+		return nil
+	}
+
+	data := generated{Package: pkg}
+	consts := make(map[string]string, len(names))
+	for _, name := range names {
+		ident := prefix + idents[name]
+		if r, _ := utf8.DecodeRuneInString(ident); !unicode.IsLetter(r) {
+			ident = defaultPrefix + ident
+		}
+		if err := declare(ident, name); err != nil {
+			return nil, err
+		}
+		consts[name] = ident
+		data.Constants = append(data.Constants, constant{Ident: ident, Name: name})
+	}
+	for _, name := range slices.Sorted(maps.Keys(types)) {
+		if _, ok := idents[name]; !ok {
+			return nil, fmt.Errorf("types: template %q is not registered", name)
+		}
+		h := helper{Func: "Render" + idents[name], Const: consts[name], Name: name, Type: types[name]}
+		if err := declare(h.Func, name); err != nil {
+			return nil, err
+		}
+		data.Helpers = append(data.Helpers, h)
+	}
+
+	var b bytes.Buffer
+	if err := generatedTemplate.Execute(&b, data); err != nil {
+		return nil, err
+	}
+	return format.Source(b.Bytes())
+}
+
+// identifiers returns an exported Go identifier for every template name,
+// without the file extension unless two names would collide
+func identifiers(names []string) map[string]string {
+	idents := make(map[string]string, len(names))
+	count := make(map[string]int)
+	for _, name := range names {
+		ident := camelCase(strings.TrimSuffix(name, path.Ext(name)))
+		idents[name] = ident
+		count[ident]++
+	}
+	for _, name := range names {
+		if count[idents[name]] > 1 {
+			idents[name] = camelCase(name)
+		}
+	}
+	return idents
+}
+
+// camelCase joins the alphanumeric words of s, each starting with an upper case letter
+func camelCase(s string) string {
+	var b strings.Builder
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+	return b.String()
+}
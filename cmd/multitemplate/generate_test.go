@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const expectedGenerated = `// Code generated by multitemplate generate; DO NOT EDIT.
+
+package views
+
+import "github.com/gin-gonic/gin"
+
+// Names of the templates registered by LoadDirectory
+const (
+	TemplateAdminUsers = "admin/users.html"
+	TemplateIndexHtml  = "index.html"
+	TemplateIndexTxt   = "index.txt"
+)
+
+// RenderAdminUsers renders the "admin/users.html" template
+func RenderAdminUsers(c *gin.Context, code int, data UsersData) {
+	c.HTML(code, TemplateAdminUsers, data)
+}
+`
+
+func TestGenerate(t *testing.T) {
+	names := []string{"admin/users.html", "index.html", "index.txt"}
+	src, err := generate("views", "Template", names, map[string]string{"admin/users.html": "UsersData"})
+	assert.NoError(t, err)
+	assert.Equal(t, expectedGenerated, string(src))
+
+	src, err = generate("views", "T", names[:1], nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(src), "import")
+	assert.Contains(t, string(src), `TAdminUsers = "admin/users.html"`)
+
+	_, err = generate("views", "Template", names, map[string]string{"missing.html": "Data"})
+	assert.EqualError(t, err, `types: template "missing.html" is not registered`)
+}
+
+func TestGenerateIdentifiers(t *testing.T) {
+	src, err := generate("views", "", []string{"404.html", "index.html"}, map[string]string{"404.html": "any"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), `Template404 = "404.html"`)
+	assert.Contains(t, string(src), `Index       = "index.html"`)
+	assert.Contains(t, string(src), `func Render404(c *gin.Context, code int, data any) {`)
+
+	for _, tc := range []struct {
+		names    []string
+		prefix   string
+		types    map[string]string
+		expected string
+	}{
+		{
+			names:    []string{"users-list.html", "users/list.html"},
+			prefix:   "Template",
+			expected: `templates "users-list.html" and "users/list.html" have the same identifier TemplateUsersListHtml`,
+		},
+		{
+			names:    []string{"a-b.html", "a_b.html"},
+			prefix:   "Template",
+			expected: `templates "a-b.html" and "a_b.html" have the same identifier TemplateABHtml`,
+		},
+		{
+			names:    []string{"index.html"},
+			prefix:   "Render",
+			types:    map[string]string{"index.html": "any"},
+			expected: `template "index.html": constant and helper have the same identifier RenderIndex, change -prefix`,
+		},
+	} {
+		_, err := generate("views", tc.prefix, tc.names, tc.types)
+		assert.EqualError(t, err, tc.expected)
+	}
+}
+
+func TestCamelCase(t *testing.T) {
+	assert.Equal(t, "AdminUserList", camelCase("admin/user-list"))
+	assert.Equal(t, "Page2", camelCase("page_2"))
+	assert.Equal(t, "", camelCase("--"))
+}
+
+func TestRunGenerate(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"generate", "-package", "views", "-o", "-", "../../tests/partials"}
+	assert.Equal(t, exitOK, run(args, &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), `TemplateLayout = "layout.html"`)
+	assert.Contains(t, stdout.String(), `TemplatePage   = "page.html"`)
+
+	output := filepath.Join(t.TempDir(), "templates_gen.go")
+	t.Setenv("GOPACKAGE", "views")
+	args = []string{"generate", "-o", output, "../../tests/partials"}
+	assert.Equal(t, exitOK, run(args, &stdout, &stderr), stderr.String())
+	src, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "package views")
+
+	t.Setenv("GOPACKAGE", "")
+	assert.Equal(t, exitUsage, run([]string{"generate", "../../tests/partials"}, &stdout, &stderr))
+	args = []string{"generate", "-package", "views", "-config", "../../tests/lint/multitemplate.json"}
+	assert.Equal(t, exitIssues, run(args, &stdout, &stderr), "templates that do not parse")
+}
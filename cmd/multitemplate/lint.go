@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-contrib/multitemplate"
+)
+
+// runLint implements the lint command
+func runLint(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configFile := flags.String("config", "", "config file describing the template sets")
+	strict := flags.Bool("strict", false, "exit with status 1 on warnings too")
+	catalogDir := flags.String("catalog", "", "directory of message catalogs the T calls are checked against")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 1 {
+		return exitUsage
+	}
+
+	cfg, err := loadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "multitemplate: %v\n", err)
+		return exitUsage
+	}
+	if flags.NArg() == 1 {
+		cfg.Root = flags.Arg(0)
+	}
+
+	if *catalogDir != "" {
+		cfg.Catalog = *catalogDir
+	}
+	if cfg.Catalog != "" {
+		cfg.Funcs = append(cfg.Funcs, "T")
+	}
+
+	issues, err := multitemplate.Lint(os.DirFS(cfg.Root), cfg.loadConfigs()...)
+	if err != nil {
+		fmt.Fprintf(stderr, "multitemplate: %v\n", err)
+		return exitUsage
+	}
+	if cfg.Catalog != "" {
+		catalogIssues, err := lintCatalog(cfg)
+		if err != nil {
+			fmt.Fprintf(stderr, "multitemplate: %v\n", err)
+			return exitUsage
+		}
+		issues = append(issues, catalogIssues...)
+	}
+
+	code := exitOK
+	for _, issue := range issues {
+		issue.File = filepath.Join(cfg.Root, filepath.FromSlash(issue.File))
+		fmt.Fprintln(stdout, issue)
+		if issue.Severity == multitemplate.SeverityError || *strict {
+			code = exitIssues
+		}
+	}
+	return code
+}
+
+// lintCatalog reports the messages used by the templates that are missing
+// from the catalog
+func lintCatalog(cfg config) ([]multitemplate.LintIssue, error) {
+	catalog, err := multitemplate.LoadCatalog(os.DirFS(cfg.Catalog), multitemplate.CatalogConfig{})
+	if err != nil {
+		return nil, err
+	}
+	return catalog.Lint(os.DirFS(cfg.Root), cfg.loadConfigs()...)
+}
@@ -0,0 +1,64 @@
+// Command multitemplate checks the templates of a gin-contrib/multitemplate
+// application from the command line, e.g. in CI.
+//
+// Usage:
+//
+//	multitemplate lint [-config multitemplate.json] [-strict] [-catalog locales] [root]
+//	multitemplate generate [-config multitemplate.json] [-o templates_gen.go] [-package name] [-prefix Template] [root]
+//
+// generate writes a constant for every template name and typed render helpers
+// for the templates listed in the "types" of the config file. It is meant to
+// be run by go generate:
+//
+//	//go:generate go run github.com/gin-contrib/multitemplate/cmd/multitemplate generate -config multitemplate.json
+//
+// lint with a catalog also reports the {{ T "key" }} calls whose message is
+// missing from a locale of the message catalogs.
+//
+// The templates under root are composed the way LoadDirectory does, using the
+// sets declared in the config file. See config.go for its format.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes
+const (
+	exitOK     = 0
+	exitIssues = 1
+	exitUsage  = 2
+)
+
+const usage = `usage: multitemplate <command> [arguments]
+
+commands:
+  lint        report template errors, exiting with status 1 when there are any
+  generate    write Go constants and typed render helpers for the template names
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command described by args and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	switch args[0] {
+	case "lint":
+		return runLint(args[1:], stdout, stderr)
+	case "generate":
+		return runGenerate(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "multitemplate: unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitUsage, run(nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "usage: multitemplate")
+	assert.Equal(t, exitUsage, run([]string{"unknown"}, &stdout, &stderr))
+	assert.Equal(t, exitOK, run([]string{"help"}, &stdout, &stderr))
+}
+
+func TestLint(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"lint", "-config", "../../tests/lint/multitemplate.json"}, &stdout, &stderr)
+	assert.Equal(t, exitIssues, code, stderr.String())
+	assert.Contains(t, stdout.String(),
+		filepath.FromSlash("../../tests/lint/partials/nav.html")+`:1:36: error: template "menu" is not defined`)
+	assert.NotContains(t, stdout.String(), `function "upper" not defined`)
+}
+
+func TestLintClean(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"lint", "../../tests/funcs"}, &stdout, &stderr)
+	assert.Equal(t, exitIssues, code, "upper is not declared without a config")
+	assert.Contains(t, stdout.String(), `function "upper" not defined`)
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"lint", "../../tests/partials"}, &stdout, &stderr), stdout.String())
+	assert.Equal(t, exitOK, run([]string{"lint", "../../tests/lint-unused"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), `warning: template "unused" is never called`)
+	assert.Equal(t, exitIssues, run([]string{"lint", "-strict", "../../tests/lint-unused"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"lint", "-config", "missing.json"}, &stdout, &stderr))
+}
+
+func TestLintCatalog(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitIssues, run([]string{"lint", "../../tests/i18n/templates"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), `function "T" not defined`)
+
+	stdout.Reset()
+	code := run([]string{"lint", "-catalog", "../../tests/i18n/locales", "../../tests/i18n/templates"}, &stdout, &stderr)
+	assert.Equal(t, exitIssues, code, stderr.String())
+	assert.Contains(t, stdout.String(),
+		filepath.FromSlash("../../tests/i18n/templates/page.html")+`:1:31: error: message "greeting" is missing in de`)
+	assert.NotContains(t, stdout.String(), `function "T" not defined`)
+}
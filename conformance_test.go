@@ -0,0 +1,96 @@
+package multitemplate
+
+import (
+	"html/template"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// delimsOptions and delimsFuncs are used by every loader in the conformance test
+var (
+	delimsOptions = *NewTemplateOptions(Delims("[[", "]]"))
+	delimsFuncs   = template.FuncMap{"upper": strings.ToUpper}
+	delimsFiles   = []string{"tests/delims/layouts/layout.html", "tests/delims/pages/page.html"}
+)
+
+const delimsOutput = "<p>Test</p> <div>{{ message }}</div>\nHi GIN\n"
+
+// conformanceLoaders register the same template set with every loader that
+// accepts TemplateOptions and return the name it was registered under
+var conformanceLoaders = map[string]func(t *testing.T, r Renderer) string{
+	"files": func(t *testing.T, r Renderer) string {
+		r.AddFromFilesFuncsWithOptions("index", delimsFuncs, delimsOptions, delimsFiles...)
+		return "index"
+	},
+	"glob": func(t *testing.T, r Renderer) string {
+		r.AddFromGlobFuncsWithOptions("index", delimsFuncs, delimsOptions, "tests/delims/*/*.html")
+		return "index"
+	},
+	"fs": func(t *testing.T, r Renderer) string {
+		r.AddFromFSFuncsWithOptions("index", delimsFuncs, delimsOptions, os.DirFS("tests/delims"),
+			"layouts/layout.html", "pages/*.html")
+		return "index"
+	},
+	"strings": func(t *testing.T, r Renderer) string {
+		var sources []string
+		for _, file := range delimsFiles {
+			b, err := os.ReadFile(file)
+			assert.NoError(t, err)
+			sources = append(sources, string(b))
+		}
+		r.AddFromStringsFuncsWithOptions("index", delimsFuncs, delimsOptions, sources...)
+		return "index"
+	},
+	"directory": func(t *testing.T, r Renderer) string {
+		assert.NoError(t, r.LoadDirectory("tests/delims", LoadConfig{
+			Layouts: "layouts/*.html",
+			Pages:   "pages",
+			Funcs:   delimsFuncs,
+			Options: delimsOptions,
+		}))
+		return "page.html"
+	},
+	"directoryFS": func(t *testing.T, r Renderer) string {
+		assert.NoError(t, r.LoadFS(os.DirFS("tests/delims"), LoadConfig{
+			Layouts: "layouts/*.html",
+			Pages:   "pages",
+			Funcs:   delimsFuncs,
+			Options: delimsOptions,
+		}))
+		return "page.html"
+	},
+}
+
+func TestLoaderConformance(t *testing.T) {
+	renderers := map[string]func() Renderer{
+		"Render":            func() Renderer { return New() },
+		"DynamicRender":     func() Renderer { return NewDynamic() },
+		"SyncRender":        func() Renderer { return NewSync() },
+		"SyncRenderDynamic": func() Renderer { return NewSyncDynamic() },
+	}
+
+	for loader, load := range conformanceLoaders {
+		outputs := make(map[string]string)
+		for kind, newRenderer := range renderers {
+			r := newRenderer()
+			name := load(t, r)
+
+			router := gin.New()
+			router.HTMLRender = r
+			router.GET("/", func(c *gin.Context) {
+				c.HTML(200, name, gin.H{"title": "Test", "name": "gin"})
+			})
+			w := performRequest(router)
+			assert.Equal(t, 200, w.Code, "%s/%s", loader, kind)
+			outputs[kind] = w.Body.String()
+		}
+
+		for kind, output := range outputs {
+			assert.Equal(t, delimsOutput, output, "%s/%s", loader, kind)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package multitemplate
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contextWriter gives the renders access to the request context, where the
+// middlewares of this package keep their state in c.Keys: the block selected
+// by Partials, the locale chain of Localize and the functions of
+// BindRequestFuncs. Data providers and themes read the request itself.
+type contextWriter struct {
+	gin.ResponseWriter
+	c *gin.Context
+}
+
+// Unwrap returns the wrapped writer, so that the contextWriter can be found
+// behind the writers of other middlewares
+func (w *contextWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// nextWithContext runs the rest of the chain with c reachable from c.Writer,
+// wrapping it only when no middleware did before
+func nextWithContext(c *gin.Context) {
+	if _, ok := requestContext(c.Writer); ok {
+		c.Next()
+		return
+	}
+	w := c.Writer
+	c.Writer = &contextWriter{ResponseWriter: w, c: c}
+	c.Next()
+	c.Writer = w
+}
+
+// requestContext returns the request context bound to w by nextWithContext,
+// following the chain of writers wrapped by w
+func requestContext(w http.ResponseWriter) (*gin.Context, bool) {
+	for w != nil {
+		if cw, ok := w.(*contextWriter); ok {
+			return cw.c, true
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		w = u.Unwrap()
+	}
+	return nil, false
+}
@@ -0,0 +1,36 @@
+package multitemplate
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextWithContext(t *testing.T) {
+	router := gin.New()
+	router.Use(DataProviders(), DataProviders())
+	router.GET("/", func(c *gin.Context) {
+		wrappers := 0
+		for w := http.ResponseWriter(c.Writer); w != nil; {
+			if _, ok := w.(*contextWriter); ok {
+				wrappers++
+			}
+			u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+			if !ok {
+				break
+			}
+			w = u.Unwrap()
+		}
+		assert.Equal(t, 1, wrappers)
+
+		ctx, ok := requestContext(c.Writer)
+		assert.True(t, ok)
+		assert.Same(t, c, ctx)
+		c.Status(http.StatusNoContent)
+	})
+
+	w := performRequest(router)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -0,0 +1,152 @@
+package multitemplate
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// fixtureExt is the extension of fixture files
+const fixtureExt = ".json"
+
+var debugIndexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Templates</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; }
+th, td { border-bottom: 1px solid #ddd; padding: .4em .8em; text-align: left; vertical-align: top; }
+code { font-size: .9em; }
+</style>
+</head>
+<body>
+<h1>Templates</h1>
+<table>
+<tr><th>Name</th><th>Loader</th><th>Files</th><th>Blocks</th><th>Fixtures</th></tr>
+{{- range . }}
+<tr>
+<td><a href="{{ .Preview }}">{{ .Name }}</a></td>
+<td>{{ .Loader }}</td>
+<td>{{ range .Files }}<code>{{ . }}</code><br>{{ end }}</td>
+<td>{{ $t := . }}{{ range .Blocks }}<a href="{{ $t.Preview }}?block={{ . }}">{{ . }}</a><br>{{ end }}</td>
+<td>{{ $t := . }}{{ range .Fixtures }}<a href="{{ $t.Preview }}?fixture={{ . }}">{{ . }}</a><br>{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
+
+// debugTemplate is a row of the index page of DebugRoutes
+type debugTemplate struct {
+	TemplateInfo
+	Preview  string   `json:"preview"`
+	Fixtures []string `json:"fixtures"`
+}
+
+// DebugRoutes registers development routes on group to browse and preview the
+// templates of r:
+//
+//	GET /                list the templates, their files, blocks and fixtures
+//	GET /preview/*name   render a template, ?block=row renders one of its blocks
+//
+// A template is rendered with the JSON data of <fixtures>/<name>.json, or of
+// <fixtures>/<name>.<fixture>.json when ?fixture= is given, and with no data
+// when the file does not exist. The list is served as JSON to clients that
+// accept it. The routes are only registered in debug mode.
+func DebugRoutes(group gin.IRoutes, r Renderer, fixtures string) {
+	if !gin.IsDebugging() {
+		return
+	}
+	fixturesFS := os.DirFS(fixtures)
+
+	group.GET("/", func(c *gin.Context) {
+		prefix := strings.TrimSuffix(c.Request.URL.Path, "/")
+		list := make([]debugTemplate, 0, len(r.Names()))
+		for _, name := range r.Names() {
+			info, ok := r.Describe(name)
+			if !ok {
+				continue
+			}
+			list = append(list, debugTemplate{
+				TemplateInfo: info,
+				Preview:      prefix + "/preview/" + name,
+				Fixtures:     fixtureVariants(fixturesFS, name),
+			})
+		}
+
+		switch c.NegotiateFormat(binding.MIMEHTML, binding.MIMEJSON) {
+		case binding.MIMEJSON:
+			c.JSON(http.StatusOK, list)
+		default:
+			c.Status(http.StatusOK)
+			c.Header("Content-Type", "text/html; charset=utf-8")
+			if err := debugIndexTemplate.Execute(c.Writer, list); err != nil {
+				_ = c.Error(err)
+			}
+		}
+	})
+
+	group.GET("/preview/*name", func(c *gin.Context) {
+		name := strings.TrimPrefix(c.Param("name"), "/")
+		if !r.Has(name) {
+			c.String(http.StatusNotFound, "%v", notFoundError(name, r.Names()))
+			return
+		}
+		data, err := loadFixture(fixturesFS, name, c.Query("fixture"))
+		if err != nil {
+			c.String(http.StatusBadRequest, "fixture: %v", err)
+			return
+		}
+		c.Render(http.StatusOK, r.InstanceBlock(name, c.Query("block"), data))
+	})
+}
+
+// loadFixture decodes the fixture of the template name, nil if there is none
+func loadFixture(fsys fs.FS, name, variant string) (any, error) {
+	file := name + fixtureExt
+	if variant != "" {
+		file = name + "." + variant + fixtureExt
+	}
+	b, err := fs.ReadFile(fsys, file)
+	if errors.Is(err, fs.ErrNotExist) && variant == "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var data any
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// fixtureVariants lists the named fixtures of the template name
+func fixtureVariants(fsys fs.FS, name string) []string {
+	dir, base := path.Split(name)
+	entries, err := fs.ReadDir(fsys, path.Clean("./"+dir))
+	if err != nil {
+		return nil
+	}
+	var variants []string
+	for _, e := range entries {
+		variant, ok := strings.CutPrefix(e.Name(), base+".")
+		if !ok || e.IsDir() || !strings.HasSuffix(variant, fixtureExt) || variant == fixtureExt[1:] {
+			continue
+		}
+		variants = append(variants, strings.TrimSuffix(variant, fixtureExt))
+	}
+	return variants
+}
@@ -0,0 +1,79 @@
+package multitemplate
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func debugRouter() *gin.Engine {
+	r := NewSync()
+	r.AddFromFiles("users.html", "tests/blocks/users.html")
+	r.AddFromString("admin/users.html", "<h1>{{ .Title }}</h1>")
+	r.AddFromString("plain", "plain {{ . }}")
+
+	router := gin.New()
+	DebugRoutes(router.Group("/debug/templates"), r, "tests/fixtures")
+	return router
+}
+
+func TestDebugRoutesIndex(t *testing.T) {
+	router := debugRouter()
+
+	w := performRequestPath(router, "/debug/templates/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `<a href="/debug/templates/preview/users.html">users.html</a>`)
+	assert.Contains(t, w.Body.String(), `<code>tests/blocks/users.html</code>`)
+	assert.Contains(t, w.Body.String(), `<a href="/debug/templates/preview/users.html?block=row">row</a>`)
+	assert.Contains(t, w.Body.String(), `<a href="/debug/templates/preview/users.html?fixture=empty">empty</a>`)
+
+	w = performRequestHeader(router, "/debug/templates/", http.Header{"Accept": {"application/json"}})
+	var list []debugTemplate
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list, 3)
+	assert.Equal(t, "admin/users.html", list[0].Name)
+	assert.Equal(t, []string{"broken", "empty"}, list[2].Fixtures)
+	assert.Equal(t, []string{"row"}, list[2].Blocks)
+}
+
+func TestDebugRoutesPreview(t *testing.T) {
+	router := debugRouter()
+
+	w := performRequestPath(router, "/debug/templates/preview/users.html")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<tr><td>tom</td></tr>")
+
+	w = performRequestPath(router, "/debug/templates/preview/users.html?fixture=empty")
+	assert.NotContains(t, w.Body.String(), "<tr>")
+
+	w = performRequestPath(router, "/debug/templates/preview/users.html?block=row")
+	assert.Equal(t, "<tr><td>[tom]</td></tr>", w.Body.String())
+
+	w = performRequestPath(router, "/debug/templates/preview/admin/users.html")
+	assert.Equal(t, "<h1>Users</h1>", w.Body.String())
+
+	w = performRequestPath(router, "/debug/templates/preview/plain")
+	assert.Equal(t, "plain ", w.Body.String(), "templates without a fixture get no data")
+
+	w = performRequestPath(router, "/debug/templates/preview/users.html?fixture=broken")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = performRequestPath(router, "/debug/templates/preview/users.html?fixture=missing")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = performRequestPath(router, "/debug/templates/preview/users.html?fixture=../../../go.mod")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performRequestPath(router, "/debug/templates/preview/user.html")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `did you mean "users.html"?`)
+}
+
+func TestDebugRoutesReleaseMode(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(gin.DebugMode)
+
+	w := performRequestPath(debugRouter(), "/debug/templates/")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,175 @@
+package multitemplate
+
+import (
+	"errors"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// LoadConfig describes how LoadDirectory and LoadFS compose templates.
+// Every page found under Pages is combined with the layouts and partials
+// into its own template set, registered under the page path relative to
+// Pages, e.g. "index.html" or "admin/users.html".
+type LoadConfig struct {
+	// Layouts is a glob pattern, relative to the root, matching the layout
+	// files. The first matching layout is the template executed on render.
+	Layouts string
+	// Partials is a glob pattern, relative to the root, matching files
+	// shared by every page. It may be empty.
+	Partials string
+	// Pages is the directory, relative to the root, walked recursively for pages.
+	Pages string
+	// Extensions restricts pages to files with one of these extensions.
+	// Every file is a page when it is empty.
+	Extensions []string
+	// Funcs are the functions available to the templates of this config.
+	Funcs template.FuncMap
+	// Options are the template options, e.g. delimiters, of this config.
+	Options TemplateOptions
+}
+
+// templateSet is a template name together with the files that compose it
+type templateSet struct {
+	name   string
+	files  []string
+	config *LoadConfig
+}
+
+// planDirectory resolves the template sets described by the configs. Paths
+// are slash separated and relative to the root of fsys.
+func planDirectory(fsys fs.FS, configs []LoadConfig) ([]templateSet, error) {
+	var sets []templateSet
+	for i := range configs {
+		cfg := &configs[i]
+		shared, err := globAll(fsys, cfg.Layouts, cfg.Partials)
+		if err != nil {
+			return nil, err
+		}
+
+		pages := path.Clean(cfg.Pages)
+		err = fs.WalkDir(fsys, pages, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(d.Name(), ".") && file != pages {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() || !hasExtension(file, cfg.Extensions) {
+				return nil
+			}
+
+			name := strings.TrimPrefix(file, pages+"/")
+			if pages == "." {
+				name = file
+			}
+			files := slices.Concat(shared, []string{file})
+			sets = append(sets, templateSet{name: name, files: files, config: cfg})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sets, nil
+}
+
+// globAll expands every non-empty pattern, keeping the order of the patterns
+func globAll(fsys fs.FS, patterns ...string) ([]string, error) {
+	var files []string
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		list, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, list...)
+	}
+	return files, nil
+}
+
+func hasExtension(file string, extensions []string) bool {
+	if len(extensions) == 0 {
+		return true
+	}
+	return slices.Contains(extensions, path.Ext(file))
+}
+
+// loadDirectory registers every template set found under root with add
+func loadDirectory[T any](
+	root string,
+	configs []LoadConfig,
+	add func(string, template.FuncMap, TemplateOptions, ...string) (T, error),
+) error {
+	sets, err := planDirectory(os.DirFS(root), configs)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, set := range sets {
+		files := make([]string, len(set.files))
+		for i, file := range set.files {
+			files[i] = filepath.Join(root, filepath.FromSlash(file))
+		}
+		if _, err := add(set.name, set.config.Funcs, set.config.Options, files...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// loadFS registers every template set found in fsys with add
+func loadFS[T any](
+	fsys fs.FS,
+	configs []LoadConfig,
+	add func(string, template.FuncMap, TemplateOptions, fs.FS, ...string) (T, error),
+) error {
+	sets, err := planDirectory(fsys, configs)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, set := range sets {
+		if _, err := add(set.name, set.config.Funcs, set.config.Options, fsys, set.files...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LoadDirectory registers a template for every page under root, composed with
+// the layouts and partials of its LoadConfig. Pass several configs to use
+// different layouts for different groups of pages. All parse errors are
+// returned together.
+func (r Render) LoadDirectory(root string, configs ...LoadConfig) error {
+	return loadDirectory(root, configs, r.TryAddFromFilesFuncsWithOptions)
+}
+
+// LoadFS is like LoadDirectory but reads the templates from fsys (e.g. embed.FS)
+func (r Render) LoadFS(fsys fs.FS, configs ...LoadConfig) error {
+	return loadFS(fsys, configs, r.TryAddFromFSFuncsWithOptions)
+}
+
+// LoadDirectory registers a template for every page under root, composed with
+// the layouts and partials of its LoadConfig. Pass several configs to use
+// different layouts for different groups of pages. All parse errors are
+// returned together.
+func (r DynamicRender) LoadDirectory(root string, configs ...LoadConfig) error {
+	return loadDirectory(root, configs, r.TryAddFromFilesFuncsWithOptions)
+}
+
+// LoadFS is like LoadDirectory but reads the templates from fsys (e.g. embed.FS)
+func (r DynamicRender) LoadFS(fsys fs.FS, configs ...LoadConfig) error {
+	return loadFS(fsys, configs, r.TryAddFromFSFuncsWithOptions)
+}
@@ -0,0 +1,96 @@
+package multitemplate
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var siteConfigs = []LoadConfig{
+	{
+		Layouts:    "layouts/base.html",
+		Partials:   "partials/*.html",
+		Pages:      "pages",
+		Extensions: []string{".html"},
+	},
+	{
+		Layouts: "layouts/admin.html",
+		Pages:   "admin",
+	},
+}
+
+func TestPlanDirectory(t *testing.T) {
+	sets, err := planDirectory(os.DirFS("tests/site"), siteConfigs)
+	assert.NoError(t, err)
+	assert.Equal(t, []templateSet{
+		{
+			name:   "admin/users.html",
+			files:  []string{"layouts/base.html", "partials/nav.html", "pages/admin/users.html"},
+			config: &siteConfigs[0],
+		},
+		{
+			name:   "index.html",
+			files:  []string{"layouts/base.html", "partials/nav.html", "pages/index.html"},
+			config: &siteConfigs[0],
+		},
+		{
+			name:   "dashboard.html",
+			files:  []string{"layouts/admin.html", "admin/dashboard.html"},
+			config: &siteConfigs[1],
+		},
+	}, sets)
+
+	_, err = planDirectory(os.DirFS("tests/site"), []LoadConfig{{Pages: "missing"}})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func testLoadedSite(t *testing.T, r Renderer) {
+	router := gin.New()
+	router.HTMLRender = r
+	router.GET("/", func(c *gin.Context) {
+		c.HTML(200, c.Query("name"), gin.H{"title": testTemplateTitle})
+	})
+
+	for name, body := range map[string]string{
+		"index.html":       "<title>Test Multiple Template</title>\n<nav></nav>\nindex page\n",
+		"admin/users.html": "<title>Test Multiple Template</title>\n<nav></nav>\nusers page\n",
+		"dashboard.html":   "<admin>dashboard</admin>\n",
+	} {
+		w := performRequestPath(router, "/?name="+name)
+		assert.Equal(t, 200, w.Code)
+		assert.Equal(t, body, w.Body.String())
+	}
+}
+
+func TestLoadDirectory(t *testing.T) {
+	r := New()
+	assert.NoError(t, r.LoadDirectory("tests/site", siteConfigs...))
+	assert.Len(t, r, 3)
+	testLoadedSite(t, r)
+
+	d := NewDynamic()
+	assert.NoError(t, d.LoadDirectory("tests/site", siteConfigs...))
+	assert.Len(t, d, 3)
+	testLoadedSite(t, d)
+}
+
+func TestLoadFS(t *testing.T) {
+	r := New()
+	assert.NoError(t, r.LoadFS(os.DirFS("tests/site"), siteConfigs...))
+	testLoadedSite(t, r)
+
+	d := NewDynamic()
+	assert.NoError(t, d.LoadFS(os.DirFS("tests/site"), siteConfigs...))
+	testLoadedSite(t, d)
+}
+
+func TestLoadDirectoryErrors(t *testing.T) {
+	r := New()
+	assert.NoError(t, r.LoadDirectory("tests/site", siteConfigs...))
+
+	err := r.LoadDirectory("tests/site", siteConfigs...)
+	assert.ErrorIs(t, err, ErrTemplateExists)
+	assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 3)
+}
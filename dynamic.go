@@ -1,16 +1,24 @@
 package multitemplate
 
 import (
-	"fmt"
 	"html/template"
 	"io/fs"
-	"path/filepath"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/render"
 )
 
-// DynamicRender type
+// DynamicRender type is a renderer for development. Templates are parsed
+// once and cached; on every render the files behind a template are polled
+// (modification time, size and content hash) and the template is only
+// parsed again when one of them changed. If that fails, for instance because
+// a file was saved half-edited, the last good template is kept: Instance
+// renders a developer error page pointing at the problem, while Execute and
+// RenderString keep rendering the last good template.
+//
+// An unknown template name always renders a 500 error: WithMissingTemplate
+// and WithFallbackTemplate require a SyncRender, created with NewSync or
+// NewRenderer(opts...), which is dynamic in debug mode.
 type DynamicRender map[string]*templateBuilder
 
 var (
@@ -24,110 +32,35 @@ func NewDynamic() DynamicRender {
 }
 
 // NewRenderer allows create an agnostic multitemplate renderer
-// depending on enabled gin mode
-func NewRenderer() Renderer {
-	if gin.IsDebugging() {
+// depending on enabled gin mode. Without options it returns a DynamicRender
+// in debug mode and a Render otherwise; with options it returns a
+// SyncRender configured with them, dynamic in debug mode.
+func NewRenderer(opts ...Option) Renderer {
+	switch {
+	case len(opts) > 0:
+		return newSync(gin.IsDebugging(), opts)
+	case gin.IsDebugging():
 		return NewDynamic()
-	}
-	return New()
-}
-
-// Type of dynamic builder
-type builderType int
-
-// Types of dynamic builders
-const (
-	templateType builderType = iota
-	filesTemplateType
-	globTemplateType
-	fsTemplateType
-	fsFuncTemplateType
-	stringTemplateType
-	stringFuncTemplateType
-	filesFuncTemplateType
-)
-
-// Builder for dynamic templates
-type templateBuilder struct {
-	buildType       builderType
-	tmpl            *template.Template
-	templateName    string
-	files           []string
-	glob            string
-	fsys            fs.FS
-	templateString  string
-	funcMap         template.FuncMap
-	templateStrings []string
-	options         TemplateOptions
-}
-
-func (tb templateBuilder) buildTemplate() *template.Template {
-	switch tb.buildType {
-	case templateType:
-		return tb.tmpl.Delims(tb.options.LeftDelimiter, tb.options.RightDelimiter)
-	case filesTemplateType:
-		tmpl := template.Must(template.ParseFiles(tb.files...))
-		return tmpl.Delims(tb.options.LeftDelimiter, tb.options.RightDelimiter)
-	case globTemplateType:
-		tmpl := template.Must(template.ParseGlob(tb.glob))
-		return tmpl.Delims(tb.options.LeftDelimiter, tb.options.RightDelimiter)
-	case fsTemplateType:
-		tmpl := template.Must(template.ParseFS(tb.fsys, tb.files...))
-		return tmpl.Delims(tb.options.LeftDelimiter, tb.options.RightDelimiter)
-	case fsFuncTemplateType:
-		tmpl := template.New(tb.templateName).
-			Delims(tb.options.LeftDelimiter, tb.options.RightDelimiter).
-			Funcs(tb.funcMap)
-		return template.Must(tmpl.ParseFS(tb.fsys, tb.files...))
-	case stringTemplateType:
-		tmpl := template.New(tb.templateName).
-			Delims(tb.options.LeftDelimiter, tb.options.RightDelimiter)
-		return template.Must(tmpl.Parse(tb.templateString))
-	case stringFuncTemplateType:
-		tmpl := template.New(tb.templateName).
-			Delims(tb.options.LeftDelimiter, tb.options.RightDelimiter).
-			Funcs(tb.funcMap)
-		for _, ts := range tb.templateStrings {
-			tmpl = template.Must(tmpl.Parse(ts))
-		}
-		return tmpl
-	case filesFuncTemplateType:
-		tmpl := template.New(tb.templateName).
-			Delims(tb.options.LeftDelimiter, tb.options.RightDelimiter).
-			Funcs(tb.funcMap)
-		return template.Must(tmpl.ParseFiles(tb.files...))
 	default:
-		panic("Invalid builder type for dynamic template")
+		return New()
 	}
 }
 
 // Add new template
 func (r DynamicRender) Add(name string, tmpl *template.Template) {
-	if tmpl == nil {
-		panic("template cannot be nil")
+	if err := r.TryAdd(name, tmpl); err != nil {
+		panic(err)
 	}
-	if len(name) == 0 {
-		panic("template name cannot be empty")
-	}
-	builder := &templateBuilder{templateName: name, tmpl: tmpl, options: *NewTemplateOptions()}
-	builder.buildType = templateType
-	r[name] = builder
 }
 
 // AddFromFiles supply add template from files
 func (r DynamicRender) AddFromFiles(name string, files ...string) *template.Template {
-	builder := &templateBuilder{templateName: name, files: files, options: *NewTemplateOptions()}
-	builder.buildType = filesTemplateType
-	r[name] = builder
-	return builder.buildTemplate()
+	return mustTemplate(r.TryAddFromFiles(name, files...))
 }
 
 // AddFromGlob supply add template from global path
 func (r DynamicRender) AddFromGlob(name, glob string) *template.Template {
-	builder := &templateBuilder{templateName: name, glob: glob, options: *NewTemplateOptions()}
-	builder.buildType = globTemplateType
-	r[name] = builder
-	return builder.buildTemplate()
+	return mustTemplate(r.TryAddFromGlob(name, glob))
 }
 
 // AddFromFS adds a new template to the DynamicRender from the provided file system (fs.FS) and files.
@@ -141,10 +74,7 @@ func (r DynamicRender) AddFromGlob(name, glob string) *template.Template {
 // Returns:
 //   - *template.Template: The constructed template.
 func (r DynamicRender) AddFromFS(name string, fsys fs.FS, files ...string) *template.Template {
-	builder := &templateBuilder{templateName: name, fsys: fsys, files: files}
-	builder.buildType = fsTemplateType
-	r[name] = builder
-	return builder.buildTemplate()
+	return mustTemplate(r.TryAddFromFS(name, fsys, files...))
 }
 
 // AddFromFSFuncs adds a new template to the DynamicRender from the provided file system (fs.FS) and files.
@@ -164,24 +94,12 @@ func (r DynamicRender) AddFromFSFuncs(
 	fsys fs.FS,
 	files ...string,
 ) *template.Template {
-	tname := filepath.Base(files[0])
-	builder := &templateBuilder{
-		templateName: tname,
-		funcMap:      funcMap,
-		fsys:         fsys,
-		files:        files,
-	}
-	builder.buildType = fsFuncTemplateType
-	r[name] = builder
-	return builder.buildTemplate()
+	return mustTemplate(r.TryAddFromFSFuncs(name, funcMap, fsys, files...))
 }
 
 // AddFromString supply add template from strings
 func (r DynamicRender) AddFromString(name, templateString string) *template.Template {
-	builder := &templateBuilder{templateName: name, templateString: templateString, options: *NewTemplateOptions()}
-	builder.buildType = stringTemplateType
-	r[name] = builder
-	return builder.buildTemplate()
+	return mustTemplate(r.TryAddFromString(name, templateString))
 }
 
 // AddFromStringsFuncs supply add template from strings
@@ -190,14 +108,7 @@ func (r DynamicRender) AddFromStringsFuncs(
 	funcMap template.FuncMap,
 	templateStrings ...string,
 ) *template.Template {
-	builder := &templateBuilder{
-		templateName: name, funcMap: funcMap,
-		templateStrings: templateStrings,
-		options:         *NewTemplateOptions(),
-	}
-	builder.buildType = stringFuncTemplateType
-	r[name] = builder
-	return builder.buildTemplate()
+	return mustTemplate(r.TryAddFromStringsFuncs(name, funcMap, templateStrings...))
 }
 
 // AddFromStringsFuncsWithOptions supply add template from strings with options
@@ -207,53 +118,189 @@ func (r DynamicRender) AddFromStringsFuncsWithOptions(
 	options TemplateOptions,
 	templateStrings ...string,
 ) *template.Template {
-	builder := &templateBuilder{
-		templateName:    name,
-		funcMap:         funcMap,
-		templateStrings: templateStrings,
-		options:         options,
-	}
-	builder.buildType = stringFuncTemplateType
-	r[name] = builder
-	return builder.buildTemplate()
+	return mustTemplate(r.TryAddFromStringsFuncsWithOptions(name, funcMap, options, templateStrings...))
 }
 
 // AddFromFilesFuncs supply add template from file callback func
 func (r DynamicRender) AddFromFilesFuncs(name string, funcMap template.FuncMap, files ...string) *template.Template {
-	tname := filepath.Base(files[0])
-	builder := &templateBuilder{templateName: tname, funcMap: funcMap, files: files, options: *NewTemplateOptions()}
-	builder.buildType = filesFuncTemplateType
-	r[name] = builder
-	return builder.buildTemplate()
+	return mustTemplate(r.TryAddFromFilesFuncs(name, funcMap, files...))
 }
 
-// AddFromFilesFuncs supply add template from file callback func
+// AddFromFilesFuncsWithOptions supply add template from file callback func with options
 func (r DynamicRender) AddFromFilesFuncsWithOptions(
 	name string,
 	funcMap template.FuncMap,
 	options TemplateOptions,
 	files ...string,
 ) *template.Template {
-	tname := filepath.Base(files[0])
-	builder := &templateBuilder{
-		templateName: tname,
-		funcMap:      funcMap,
-		files:        files,
-		options:      options,
+	return mustTemplate(r.TryAddFromFilesFuncsWithOptions(name, funcMap, options, files...))
+}
+
+// AddFromGlobFuncsWithOptions supply add template from global path with callback func and options
+func (r DynamicRender) AddFromGlobFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	glob string,
+) *template.Template {
+	return mustTemplate(r.TryAddFromGlobFuncsWithOptions(name, funcMap, options, glob))
+}
+
+// AddFromFSFuncsWithOptions supply add template from fs.FS (e.g. embed.FS) with callback func and options
+func (r DynamicRender) AddFromFSFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	fsys fs.FS,
+	files ...string,
+) *template.Template {
+	return mustTemplate(r.TryAddFromFSFuncsWithOptions(name, funcMap, options, fsys, files...))
+}
+
+// TryAdd is like Add but returns an error instead of panicking
+func (r DynamicRender) TryAdd(name string, tmpl *template.Template) error {
+	if tmpl == nil {
+		return &TemplateError{Name: name, Err: ErrNilTemplate}
+	}
+	if len(name) == 0 {
+		return &TemplateError{Err: ErrEmptyName}
 	}
-	builder.buildType = filesFuncTemplateType
-	r[name] = builder
-	return builder.buildTemplate()
+	r[name] = newTemplateBuilder(name, tmpl)
+	return nil
 }
 
-// Instance supply render string
+// TryAddFromFiles is like AddFromFiles but returns an error instead of panicking
+func (r DynamicRender) TryAddFromFiles(name string, files ...string) (*template.Template, error) {
+	return r.addBuilder(newFilesBuilder(name, nil, *NewTemplateOptions(), files))
+}
+
+// TryAddFromGlob is like AddFromGlob but returns an error instead of panicking
+func (r DynamicRender) TryAddFromGlob(name, glob string) (*template.Template, error) {
+	return r.addBuilder(newGlobBuilder(name, nil, *NewTemplateOptions(), glob))
+}
+
+// TryAddFromFS is like AddFromFS but returns an error instead of panicking
+func (r DynamicRender) TryAddFromFS(name string, fsys fs.FS, files ...string) (*template.Template, error) {
+	return r.addBuilder(newFSBuilder(name, nil, *NewTemplateOptions(), fsys, files))
+}
+
+// TryAddFromFSFuncs is like AddFromFSFuncs but returns an error instead of panicking
+func (r DynamicRender) TryAddFromFSFuncs(
+	name string,
+	funcMap template.FuncMap,
+	fsys fs.FS,
+	files ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newFSBuilder(name, funcMap, *NewTemplateOptions(), fsys, files))
+}
+
+// TryAddFromString is like AddFromString but returns an error instead of panicking
+func (r DynamicRender) TryAddFromString(name, templateString string) (*template.Template, error) {
+	return r.addBuilder(newStringsBuilder(name, nil, *NewTemplateOptions(), []string{templateString}))
+}
+
+// TryAddFromStringsFuncs is like AddFromStringsFuncs but returns an error instead of panicking
+func (r DynamicRender) TryAddFromStringsFuncs(
+	name string,
+	funcMap template.FuncMap,
+	templateStrings ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newStringsBuilder(name, funcMap, *NewTemplateOptions(), templateStrings))
+}
+
+// TryAddFromStringsFuncsWithOptions is like AddFromStringsFuncsWithOptions
+// but returns an error instead of panicking
+func (r DynamicRender) TryAddFromStringsFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	templateStrings ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newStringsBuilder(name, funcMap, options, templateStrings))
+}
+
+// TryAddFromFilesFuncs is like AddFromFilesFuncs but returns an error instead of panicking
+func (r DynamicRender) TryAddFromFilesFuncs(
+	name string,
+	funcMap template.FuncMap,
+	files ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newFilesBuilder(name, funcMap, *NewTemplateOptions(), files))
+}
+
+// TryAddFromFilesFuncsWithOptions is like AddFromFilesFuncsWithOptions
+// but returns an error instead of panicking
+func (r DynamicRender) TryAddFromFilesFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	files ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newFilesBuilder(name, funcMap, options, files))
+}
+
+// TryAddFromGlobFuncsWithOptions is like AddFromGlobFuncsWithOptions
+// but returns an error instead of panicking
+func (r DynamicRender) TryAddFromGlobFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	glob string,
+) (*template.Template, error) {
+	return r.addBuilder(newGlobBuilder(name, funcMap, options, glob))
+}
+
+// TryAddFromFSFuncsWithOptions is like AddFromFSFuncsWithOptions
+// but returns an error instead of panicking
+func (r DynamicRender) TryAddFromFSFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	fsys fs.FS,
+	files ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newFSBuilder(name, funcMap, options, fsys, files))
+}
+
+// addBuilder validates the template described by b by building it once
+// and registers the builder when it succeeds
+func (r DynamicRender) addBuilder(b *templateBuilder) (*template.Template, error) {
+	if len(b.name) == 0 {
+		return nil, &TemplateError{Err: ErrEmptyName}
+	}
+	tmpl, err := b.template()
+	if err != nil {
+		return nil, err
+	}
+	r[b.name] = b
+	return tmpl, nil
+}
+
+// Instance supply render string. When the template fails to rebuild it
+// returns a render.Render that responds with an error page. A name of the
+// form "page#block" renders only the block of that template, as does a request
+// marked partial by the Partials middleware. The Localize middleware selects a
+// locale variant of the name. An unknown name renders a 500 error suggesting
+// similar template names.
 func (r DynamicRender) Instance(name string, data interface{}) render.Render {
+	return newPartialRender(r, name, data)
+}
+
+// InstanceBlock renders only the template defined as block in the template
+// registered under name, e.g. a {{ define "row" }} fragment for htmx. An empty
+// block renders the whole template.
+func (r DynamicRender) InstanceBlock(name, block string, data any) render.Render {
 	builder, ok := r[name]
 	if !ok {
-		panic(fmt.Sprintf("Dynamic template with name %s not found", name))
+		return internalError{err: notFoundError(name, r.Names())}
+	}
+	tmpl, err := builder.template()
+	if err != nil {
+		return errorPage{err: err}
 	}
-	return render.HTML{
-		Template: builder.buildTemplate(),
-		Data:     data,
+	tmpl, err = lookupBlock(tmpl, name, block)
+	if err != nil {
+		return internalError{err: err}
 	}
+	return newHTML(tmpl, data)
 }
@@ -2,8 +2,13 @@ package multitemplate
 
 import (
 	"html/template"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -169,9 +174,11 @@ func TestPanicInvalidTypeBuilder(t *testing.T) {
 func TestTemplateNotFound(t *testing.T) {
 	r := make(DynamicRender)
 	r.AddFromString("index", "This is a test template")
-	assert.Panics(t, func() {
-		r.Instance("NotFoundTemplate", nil)
-	})
+	w := httptest.NewRecorder()
+	err := r.Instance("indx", nil).Render(w)
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), `did you mean "index"?`)
 }
 
 func TestNotDynamicMode(t *testing.T) {
@@ -217,3 +224,73 @@ func TestAddFromFSFuncsDynamic(t *testing.T) {
 	assert.Equal(t, 200, w.Code)
 	assert.Equal(t, "<p>Test Multiple Template</p>\nHi, this is article template\n", w.Body.String())
 }
+
+func TestTryAddFromFilesDynamic(t *testing.T) {
+	r := NewDynamic()
+	_, err := r.TryAddFromFiles("index", "tests/base.html", "tests/missing.html")
+	var te *TemplateError
+	assert.ErrorAs(t, err, &te)
+	assert.Equal(t, "index", te.Name)
+	assert.Equal(t, "tests/missing.html", te.File)
+	assert.NotContains(t, r, "index")
+
+	_, err = r.TryAddFromFiles("", "tests/base.html", "tests/article.html")
+	assert.ErrorIs(t, err, ErrEmptyName)
+
+	tmpl, err := r.TryAddFromFiles("index", "tests/base.html", "tests/article.html")
+	assert.NoError(t, err)
+	assert.NotNil(t, tmpl)
+	assert.Contains(t, r, "index")
+}
+
+func TestDynamicReloadOnChange(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "index.html")
+	assert.NoError(t, os.WriteFile(file, []byte("Hello {{ .name }}"), 0o600))
+
+	r := NewDynamic()
+	first := r.AddFromFiles("index", file)
+
+	tmpl, err := r["index"].template()
+	assert.NoError(t, err)
+	assert.Same(t, first, tmpl, "unchanged files are not parsed again")
+
+	// touching the file without changing its content keeps the template
+	later := time.Now().Add(time.Hour)
+	assert.NoError(t, os.Chtimes(file, later, later))
+	tmpl, err = r["index"].template()
+	assert.NoError(t, err)
+	assert.Same(t, first, tmpl)
+
+	assert.NoError(t, os.WriteFile(file, []byte("Bye {{ .name }}"), 0o600))
+	tmpl, err = r["index"].template()
+	assert.NoError(t, err)
+	assert.NotSame(t, first, tmpl)
+
+	var b strings.Builder
+	assert.NoError(t, tmpl.Execute(&b, gin.H{"name": "gin"}))
+	assert.Equal(t, "Bye gin", b.String())
+}
+
+func TestDynamicReloadGlob(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.html"), []byte(`{{template "b.html"}}`), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.html"), []byte(`b`), 0o600))
+
+	r := NewDynamic()
+	first := r.AddFromGlob("index", filepath.Join(dir, "*.html"))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "c.html"), []byte(`c`), 0o600))
+	tmpl, err := r["index"].template()
+	assert.NoError(t, err)
+	assert.NotSame(t, first, tmpl, "new files matching the glob trigger a rebuild")
+	assert.NotNil(t, tmpl.Lookup("c.html"))
+}
+
+func TestDynamicStringTemplateIsCached(t *testing.T) {
+	r := NewDynamic()
+	first := r.AddFromString("index", "Welcome to {{ .name }} template")
+	tmpl, err := r["index"].template()
+	assert.NoError(t, err)
+	assert.Same(t, first, tmpl)
+}
@@ -0,0 +1,161 @@
+package multitemplate
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// snippetContext is the number of source lines shown around the error line
+const snippetContext = 3
+
+var errorPageTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Template error</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h1 { color: #b00020; font-size: 1.4em; }
+.location { font-family: monospace; font-size: 1.1em; }
+pre { background: #f6f6f6; border: 1px solid #ddd; padding: 1em; overflow-x: auto; }
+.line { display: block; }
+.line.error { background: #fdd; }
+.lineno { color: #999; display: inline-block; width: 4em; }
+</style>
+</head>
+<body>
+<h1>Template error{{ with .Name }} in {{ printf "%q" . }}{{ end }}</h1>
+{{ with .Location }}<p class="location">{{ . }}</p>{{ end }}
+<pre>{{ .Message }}</pre>
+{{ with .Snippet }}<pre>
+{{- range . -}}
+<span class="line{{ if .Error }} error{{ end }}"><span class="lineno">{{ .Number }}</span>{{ .Text }}</span>
+{{- if and .Error $.Column }}<span class="line"><span class="lineno"></span>{{ $.Caret }}^</span>{{ end }}
+{{- end -}}
+</pre>{{ end }}
+</body>
+</html>
+`))
+
+// errorPage is the render.Render shown instead of a template that failed to
+// build. It responds with a 500 status and describes the error, including the
+// surrounding source when the error position is known.
+type errorPage struct {
+	err error
+}
+
+type snippetLine struct {
+	Number int
+	Text   string
+	Error  bool
+}
+
+type errorPageData struct {
+	Name     string
+	Location string
+	Message  string
+	Column   int
+	Caret    string
+	Snippet  []snippetLine
+}
+
+// Render writes the error page and returns the error so gin records it
+func (p errorPage) Render(w http.ResponseWriter) error {
+	p.WriteContentType(w)
+	w.WriteHeader(http.StatusInternalServerError)
+	if err := errorPageTemplate.Execute(w, p.data()); err != nil {
+		return err
+	}
+	return p.err
+}
+
+// WriteContentType writes the HTML content type
+func (p errorPage) WriteContentType(w http.ResponseWriter) {
+	header := w.Header()
+	if val := header["Content-Type"]; len(val) == 0 {
+		header["Content-Type"] = []string{"text/html; charset=utf-8"}
+	}
+}
+
+func (p errorPage) data() errorPageData {
+	data := errorPageData{Message: p.err.Error()}
+
+	var te *TemplateError
+	if !errors.As(p.err, &te) {
+		return data
+	}
+	data.Name = te.Name
+	data.Message = te.Err.Error()
+	data.Location = te.File
+	switch {
+	case te.Line > 0 && te.File == "":
+		data.Location = "line " + strconv.Itoa(te.Line)
+	case te.Line > 0:
+		data.Location += ":" + strconv.Itoa(te.Line)
+	}
+	if te.Line > 0 && te.Column > 0 {
+		data.Location += ":" + strconv.Itoa(te.Column)
+	}
+	data.Column = te.Column
+	if te.Column > 1 {
+		data.Caret = strings.Repeat(" ", te.Column-1)
+	}
+	data.Snippet = snippet(te.source, te.Line)
+	return data
+}
+
+// snippet returns the lines of source around line
+func snippet(source string, line int) []snippetLine {
+	if source == "" || line <= 0 {
+		return nil
+	}
+	lines := strings.Split(source, "\n")
+	if line > len(lines) {
+		return nil
+	}
+
+	from := max(line-snippetContext, 1)
+	to := min(line+snippetContext, len(lines))
+	result := make([]snippetLine, 0, to-from+1)
+	for n := from; n <= to; n++ {
+		result = append(result, snippetLine{
+			Number: n,
+			Text:   lines[n-1],
+			Error:  n == line,
+		})
+	}
+	return result
+}
+
+// internalError is the render.Render for unknown template names and failed
+// executions without an error template. It responds with a 500 status and
+// returns the error so that gin records it. The error is only written to the
+// response in debug mode.
+type internalError struct {
+	err error
+}
+
+// Render writes the 500 response
+func (m internalError) Render(w http.ResponseWriter) error {
+	m.WriteContentType(w)
+	w.WriteHeader(http.StatusInternalServerError)
+	body := http.StatusText(http.StatusInternalServerError)
+	if gin.IsDebugging() {
+		body = m.err.Error()
+	}
+	_, _ = w.Write([]byte(body))
+	return m.err
+}
+
+// WriteContentType writes the plain text content type
+func (m internalError) WriteContentType(w http.ResponseWriter) {
+	header := w.Header()
+	if val := header["Content-Type"]; len(val) == 0 {
+		header["Content-Type"] = []string{"text/plain; charset=utf-8"}
+	}
+}
@@ -0,0 +1,83 @@
+package multitemplate
+
+import (
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamicErrorPage(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "index.html")
+	assert.NoError(t, os.WriteFile(file, []byte("<p>\n{{ .title }}\n</p>"), 0o600))
+
+	r := NewDynamic()
+	good := r.AddFromFiles("index", file)
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.GET("/", func(c *gin.Context) {
+		c.HTML(200, "index", gin.H{"title": testTemplateTitle})
+	})
+
+	assert.NoError(t, os.WriteFile(file, []byte("<p>\n{{ .title }\n<b>x</b>\n</p>"), 0o600))
+	w := performRequest(router)
+	assert.Equal(t, 500, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.Contains(t, body, `Template error in &#34;index&#34;`)
+	assert.Contains(t, body, file+":2")
+	assert.Contains(t, body, `<span class="line error"><span class="lineno">2</span>{{ .title }</span>`)
+	assert.Contains(t, body, "&lt;b&gt;x&lt;/b&gt;")
+
+	tmpl, err := r["index"].template()
+	assert.Error(t, err)
+	assert.Same(t, good, tmpl, "the last good template is kept")
+
+	assert.NoError(t, os.WriteFile(file, []byte("<p>{{ .title }}</p>"), 0o600))
+	w = performRequest(router)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "<p>Test Multiple Template</p>", w.Body.String())
+}
+
+func TestErrorPageData(t *testing.T) {
+	data := errorPage{err: errors.New("boom")}.data()
+	assert.Equal(t, errorPageData{Message: "boom"}, data)
+
+	data = errorPage{err: &TemplateError{
+		Name:   "index",
+		Line:   1,
+		Column: 3,
+		Err:    errors.New("bad"),
+		source: "{{ x }}",
+	}}.data()
+	assert.Equal(t, "line 1:3", data.Location)
+	assert.Equal(t, "  ", data.Caret)
+	assert.Equal(t, []snippetLine{{Number: 1, Text: "{{ x }}", Error: true}}, data.Snippet)
+
+	w := httptest.NewRecorder()
+	err := errorPage{err: errors.New("boom")}.Render(w)
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, 500, w.Code)
+}
+
+func TestSnippet(t *testing.T) {
+	source := "1\n2\n3\n4\n5\n6\n7\n8\n9"
+	lines := snippet(source, 2)
+	assert.Len(t, lines, 5)
+	assert.Equal(t, 1, lines[0].Number)
+	assert.True(t, lines[1].Error)
+
+	lines = snippet(source, 9)
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "9", lines[3].Text)
+
+	assert.Nil(t, snippet(source, 0))
+	assert.Nil(t, snippet(source, 20))
+	assert.Nil(t, snippet("", 1))
+}
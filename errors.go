@@ -0,0 +1,74 @@
+package multitemplate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Errors reported by the TryAdd* and Execute methods, wrapped in a *TemplateError
+var (
+	ErrEmptyName        = errors.New("template name cannot be empty")
+	ErrNilTemplate      = errors.New("template cannot be nil")
+	ErrTemplateExists   = errors.New("template already exists")
+	ErrNoFiles          = errors.New("no template files")
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrBlockNotFound    = errors.New("block not found")
+	ErrFieldNotFound    = errors.New("field not found")
+	ErrInvalidOption    = errors.New("invalid template option")
+	ErrUnboundFunc      = errors.New("request function called outside of BindRequestFuncs")
+	ErrThemeNotFound    = errors.New("theme not found")
+)
+
+// TemplateError describes why a template could not be loaded or registered.
+// File, Line and Column are filled in whenever they can be determined.
+type TemplateError struct {
+	Name   string // name the template was registered under
+	File   string // source file, empty for templates built from strings
+	Line   int    // 1-based line of the error, 0 if unknown
+	Column int    // 1-based column of the error, 0 if unknown
+	Err    error
+
+	// source is the text that failed to parse, kept for error reporting
+	source string
+}
+
+func (e *TemplateError) Error() string {
+	var b strings.Builder
+	b.WriteString("multitemplate: ")
+	if e.Name != "" {
+		fmt.Fprintf(&b, "template %q: ", e.Name)
+	}
+	if e.File != "" {
+		b.WriteString(e.File)
+		if e.Line > 0 {
+			fmt.Fprintf(&b, ":%d", e.Line)
+			if e.Column > 0 {
+				fmt.Fprintf(&b, ":%d", e.Column)
+			}
+		}
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// positionPattern matches the "name:line[:column]" prefix that text/template
+// and html/template put in front of parse and execution errors.
+var positionPattern = regexp.MustCompile(`^(?:html/)?template: ?[^:]*:(\d+):(?:(\d+):)?`)
+
+// newTemplateError wraps err, extracting the line and column from its message.
+func newTemplateError(file, source string, err error) *TemplateError {
+	e := &TemplateError{File: file, Err: err, source: source}
+	if m := positionPattern.FindStringSubmatch(err.Error()); m != nil {
+		e.Line, _ = strconv.Atoi(m[1])
+		e.Column, _ = strconv.Atoi(m[2])
+	}
+	return e
+}
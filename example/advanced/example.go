@@ -2,7 +2,6 @@ package main
 
 import (
 	"log"
-	"path/filepath"
 
 	"github.com/gin-contrib/multitemplate"
 	"github.com/gin-gonic/gin"
@@ -30,22 +29,13 @@ func main() {
 func loadTemplates(templatesDir string) multitemplate.Renderer {
 	r := multitemplate.NewRenderer()
 
-	layouts, err := filepath.Glob(templatesDir + "/layouts/*.html")
-	if err != nil {
-		panic(err.Error())
-	}
-
-	includes, err := filepath.Glob(templatesDir + "/includes/*.html")
+	// Generate our templates map from our layouts/ and includes/ directories
+	err := r.LoadDirectory(templatesDir, multitemplate.LoadConfig{
+		Layouts: "layouts/*.html",
+		Pages:   "includes",
+	})
 	if err != nil {
 		panic(err.Error())
 	}
-
-	// Generate our templates map from our layouts/ and includes/ directories
-	for _, include := range includes {
-		layoutCopy := make([]string, len(layouts))
-		copy(layoutCopy, layouts)
-		layoutCopy = append(layoutCopy, include)
-		r.AddFromFiles(filepath.Base(include), layoutCopy...)
-	}
 	return r
 }
@@ -2,7 +2,6 @@ package main
 
 import (
 	"log"
-	"path/filepath"
 
 	"github.com/gin-contrib/multitemplate"
 	"github.com/gin-gonic/gin"
@@ -30,40 +29,20 @@ func main() {
 func loadTemplates(templatesDir string) multitemplate.Renderer {
 	r := multitemplate.NewRenderer()
 
-	articleLayouts, err := filepath.Glob(templatesDir + "/layouts/article-base.html")
+	// Generate our templates map from our layouts/ directory combined
+	// with the articles/ and admins/ directories
+	err := r.LoadDirectory(templatesDir,
+		multitemplate.LoadConfig{
+			Layouts: "layouts/article-base.html",
+			Pages:   "articles",
+		},
+		multitemplate.LoadConfig{
+			Layouts: "layouts/admin-base.html",
+			Pages:   "admins",
+		},
+	)
 	if err != nil {
 		panic(err.Error())
 	}
-
-	articles, err := filepath.Glob(templatesDir + "/articles/*.html")
-	if err != nil {
-		panic(err.Error())
-	}
-
-	// Generate our templates map from our articleLayouts/ and articles/ directories
-	for _, article := range articles {
-		layoutCopy := make([]string, len(articleLayouts))
-		copy(layoutCopy, articleLayouts)
-		layoutCopy = append(layoutCopy, article)
-		r.AddFromFiles(filepath.Base(article), layoutCopy...)
-	}
-
-	adminLayouts, err := filepath.Glob(templatesDir + "/layouts/admin-base.html")
-	if err != nil {
-		panic(err.Error())
-	}
-
-	admins, err := filepath.Glob(templatesDir + "/admins/*.html")
-	if err != nil {
-		panic(err.Error())
-	}
-
-	// Generate our templates map from our adminLayouts/ and admins/ directories
-	for _, admin := range admins {
-		layoutCopy := make([]string, len(adminLayouts))
-		copy(layoutCopy, adminLayouts)
-		layoutCopy = append(layoutCopy, admin)
-		r.AddFromFiles(filepath.Base(admin), layoutCopy...)
-	}
 	return r
 }
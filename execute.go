@@ -0,0 +1,171 @@
+package multitemplate
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// executeTemplate looks up name and executes it into w
+func executeTemplate(
+	lookup func(string) (*template.Template, error),
+	w io.Writer,
+	name string,
+	data any,
+) error {
+	tmpl, err := lookup(name)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
+
+// keptTemplate returns tmpl, the last good template kept when a rebuild
+// fails with err, logging the error instead of returning it. Without a last
+// good template err is returned.
+func keptTemplate(tmpl *template.Template, err error) (*template.Template, error) {
+	if err == nil || tmpl == nil {
+		return tmpl, err
+	}
+	fmt.Fprintf(gin.DefaultWriter, "[GIN-debug] [WARNING] multitemplate: serving the last good template: %v\n", err)
+	return tmpl, nil
+}
+
+// renderString executes the template registered under name and returns the output
+func renderString(lookup func(string) (*template.Template, error), name string, data any) (string, error) {
+	var b strings.Builder
+	if err := executeTemplate(lookup, &b, name, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// lookup returns the template registered under name, or its block for a
+// name of the form "page#block"
+func (r Render) lookup(name string) (*template.Template, error) {
+	name, block := splitBlock(name, r.Has)
+	tmpl, ok := r[name]
+	if !ok {
+		return nil, notFoundError(name, r.Names())
+	}
+	return lookupBlock(tmpl, name, block)
+}
+
+// Execute renders the template registered under name into w, outside of any request
+func (r Render) Execute(w io.Writer, name string, data any) error {
+	return executeTemplate(r.lookup, w, name, data)
+}
+
+// RenderString renders the template registered under name and returns the output
+func (r Render) RenderString(name string, data any) (string, error) {
+	return renderString(r.lookup, name, data)
+}
+
+// lookup returns the template registered under name, or its block for a name
+// of the form "page#block", rebuilding it when its files changed
+func (r DynamicRender) lookup(name string) (*template.Template, error) {
+	return r.lookupTemplate(name, false)
+}
+
+// served is lookup for Execute and RenderString, which keep serving the last
+// good template when a rebuild fails
+func (r DynamicRender) served(name string) (*template.Template, error) {
+	return r.lookupTemplate(name, true)
+}
+
+// lookupTemplate is lookup, returning the last good template instead of the
+// error of a failed rebuild when keep is set
+func (r DynamicRender) lookupTemplate(name string, keep bool) (*template.Template, error) {
+	name, block := splitBlock(name, r.Has)
+	builder, ok := r[name]
+	if !ok {
+		return nil, notFoundError(name, r.Names())
+	}
+	tmpl, err := builder.template()
+	if keep {
+		tmpl, err = keptTemplate(tmpl, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lookupBlock(tmpl, name, block)
+}
+
+// Execute renders the template registered under name into w, outside of any
+// request. The template is rebuilt first when its files changed; if that
+// fails, the last good template is rendered and the error is logged.
+func (r DynamicRender) Execute(w io.Writer, name string, data any) error {
+	return executeTemplate(r.served, w, name, data)
+}
+
+// RenderString renders the template registered under name and returns the output
+func (r DynamicRender) RenderString(name string, data any) (string, error) {
+	return renderString(r.served, name, data)
+}
+
+// lookup returns the template registered under name, or its block for a
+// name of the form "page#block"
+func (r *SyncRender) lookup(name string) (*template.Template, error) {
+	return r.lookupTemplate(name, false)
+}
+
+// served is lookup for Execute and RenderString, which keep serving the last
+// good template when a rebuild fails in dynamic mode
+func (r *SyncRender) served(name string) (*template.Template, error) {
+	return r.lookupTemplate(name, true)
+}
+
+// lookupTemplate is lookup, returning the last good template instead of the
+// error of a failed rebuild when keep is set
+func (r *SyncRender) lookupTemplate(name string, keep bool) (*template.Template, error) {
+	name, block := splitBlock(name, r.Has)
+	tmpl, ok, err := r.template(name)
+	if !ok {
+		return nil, notFoundError(name, r.Names())
+	}
+	if keep {
+		tmpl, err = keptTemplate(tmpl, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lookupBlock(tmpl, name, block)
+}
+
+// Execute renders the template registered under name into w, outside of any
+// request. In dynamic mode a template failing to rebuild renders its last good
+// version and the error is logged.
+func (r *SyncRender) Execute(w io.Writer, name string, data any) error {
+	return executeTemplate(r.served, w, name, data)
+}
+
+// RenderString renders the template registered under name and returns the output
+func (r *SyncRender) RenderString(name string, data any) (string, error) {
+	return renderString(r.served, name, data)
+}
+
+// Execute renders the template registered under name into w, outside of any request
+func (r *TextRender) Execute(w io.Writer, name string, data any) error {
+	name, block := splitBlock(name, r.Has)
+	tmpl, ok := r.templates[name]
+	if !ok {
+		return notFoundError(name, r.Names())
+	}
+	tmpl, err := lookupBlock(tmpl, name, block)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
+
+// RenderString renders the template registered under name and returns the output
+func (r *TextRender) RenderString(name string, data any) (string, error) {
+	var b strings.Builder
+	if err := r.Execute(&b, name, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,85 @@
+package multitemplate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecute(t *testing.T) {
+	const expected = "<p>&lt;Test&gt;</p>\nHi, this is article template\n"
+
+	for name, r := range map[string]Renderer{
+		"render":  New(),
+		"dynamic": NewDynamic(),
+		"sync":    NewSync(),
+	} {
+		r.AddFromFiles("index", "tests/base.html", "tests/article.html")
+
+		var b bytes.Buffer
+		assert.NoError(t, r.Execute(&b, "index", gin.H{"title": "<Test>"}), name)
+		assert.Equal(t, expected, b.String(), name)
+
+		s, err := r.RenderString("index", gin.H{"title": "<Test>"})
+		assert.NoError(t, err, name)
+		assert.Equal(t, expected, s, name)
+
+		_, err = r.RenderString("missing", nil)
+		assert.ErrorIs(t, err, ErrTemplateNotFound, name)
+		var te *TemplateError
+		assert.ErrorAs(t, err, &te, name)
+		assert.Equal(t, "missing", te.Name, name)
+	}
+}
+
+func TestExecuteDynamicReload(t *testing.T) {
+	for name, r := range map[string]interface {
+		Renderer
+		blockRenderer
+	}{
+		"dynamic": NewDynamic(),
+		"sync":    NewSyncDynamic(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "mail.txt")
+			assert.NoError(t, os.WriteFile(file, []byte("Hello {{ . }}"), 0o600))
+
+			r.AddFromFiles("mail", file)
+			s, err := r.RenderString("mail", "gin")
+			assert.NoError(t, err)
+			assert.Equal(t, "Hello gin", s)
+
+			assert.NoError(t, os.WriteFile(file, []byte("Bye {{ . }}"), 0o600))
+			s, err = r.RenderString("mail", "gin")
+			assert.NoError(t, err)
+			assert.Equal(t, "Bye gin", s)
+
+			assert.NoError(t, os.WriteFile(file, []byte("Bye {{ . "), 0o600))
+			s, err = r.RenderString("mail", "gin")
+			assert.NoError(t, err)
+			assert.Equal(t, "Bye gin", s, "the last good template is rendered")
+
+			_, err = r.lookup("mail")
+			var te *TemplateError
+			assert.ErrorAs(t, err, &te)
+			assert.Equal(t, file, te.File)
+		})
+	}
+}
+
+func TestExecuteText(t *testing.T) {
+	r := NewText("")
+	r.AddFromString("hello", "Hello {{ . }}")
+
+	s, err := r.RenderString("hello", "<gin>")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello <gin>", s)
+
+	_, err = r.RenderString("missing", nil)
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+}
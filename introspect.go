@@ -0,0 +1,199 @@
+package multitemplate
+
+import (
+	"html/template"
+	"maps"
+	"runtime"
+	"slices"
+	"sync"
+	"weak"
+)
+
+// Loader identifies the kind of loader that registered a template
+type Loader string
+
+// Loaders reported by Describe
+const (
+	LoaderTemplate Loader = "template" // Add
+	LoaderFiles    Loader = "files"    // AddFromFiles and LoadDirectory
+	LoaderGlob     Loader = "glob"     // AddFromGlob
+	LoaderFS       Loader = "fs"       // AddFromFS and LoadFS
+	LoaderStrings  Loader = "strings"  // AddFromString
+)
+
+// TemplateInfo describes a registered template, see Describe
+type TemplateInfo struct {
+	Name   string `json:"name"`
+	Loader Loader `json:"loader"`
+	// Files are the source files the template was built from, with globs
+	// expanded. They are relative to the fs.FS for LoaderFS.
+	Files []string `json:"files"`
+	// Patterns are the glob patterns given to AddFromGlob and the FS loaders
+	Patterns []string `json:"patterns,omitempty"`
+
+	LeftDelimiter  string `json:"leftDelimiter"`
+	RightDelimiter string `json:"rightDelimiter"`
+	// Options are the html/template options, e.g. "missingkey=error"
+	Options []string `json:"options,omitempty"`
+
+	// Funcs are the names of the functions given to the loader, sorted
+	Funcs []string `json:"funcs"`
+	// Blocks are the names of the other templates defined in the set, sorted.
+	// Each can be rendered on its own with InstanceBlock.
+	Blocks []string `json:"blocks"`
+}
+
+// loaders maps the builder types to the loaders reported by Describe
+var loaders = map[builderType]Loader{
+	templateType:       LoaderTemplate,
+	filesTemplateType:  LoaderFiles,
+	globTemplateType:   LoaderGlob,
+	fsTemplateType:     LoaderFS,
+	stringTemplateType: LoaderStrings,
+}
+
+// describe reports how the builder loads its template. tmpl is the template
+// it last built, nil if unknown.
+func (tb *templateBuilder) describe(tmpl *template.Template) TemplateInfo {
+	info := TemplateInfo{
+		Name:           tb.name,
+		Loader:         loaders[tb.buildType],
+		LeftDelimiter:  tb.options.LeftDelimiter,
+		RightDelimiter: tb.options.RightDelimiter,
+		Options:        slices.Clone(tb.options.Options),
+		Funcs:          slices.Sorted(maps.Keys(tb.funcMap)),
+		Blocks:         blocks(tmpl),
+	}
+
+	tb.mu.Lock()
+	for _, source := range tb.sources {
+		info.Files = append(info.Files, source.file)
+	}
+	tb.mu.Unlock()
+
+	switch tb.buildType {
+	case globTemplateType:
+		info.Patterns = []string{tb.glob}
+	case fsTemplateType:
+		info.Patterns = slices.Clone(tb.files)
+	case templateType, filesTemplateType, stringTemplateType:
+	}
+	return info
+}
+
+// blocks returns the names of the templates associated with tmpl, sorted
+func blocks(tmpl *template.Template) []string {
+	if tmpl == nil {
+		return nil
+	}
+	var names []string
+	for _, t := range tmpl.Templates() {
+		if t.Name() != tmpl.Name() {
+			names = append(names, t.Name())
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// renderInfo keeps what Describe reports for the templates of a Render,
+// which only stores the parsed templates. It is keyed by weak pointers so
+// that an entry is dropped once its template is garbage collected.
+var renderInfo sync.Map // weak.Pointer[template.Template] -> TemplateInfo
+
+// setRenderInfo records info for tmpl until it is garbage collected
+func setRenderInfo(tmpl *template.Template, info TemplateInfo) {
+	key := weak.Make(tmpl)
+	renderInfo.Store(key, info)
+	runtime.AddCleanup(tmpl, func(key weak.Pointer[template.Template]) {
+		renderInfo.Delete(key)
+	}, key)
+}
+
+// Names returns the registered template names, sorted
+func (r Render) Names() []string {
+	return slices.Sorted(maps.Keys(r))
+}
+
+// Has reports whether a template is registered under name
+func (r Render) Has(name string) bool {
+	_, ok := r[name]
+	return ok
+}
+
+// Describe reports how the template registered under name was loaded
+func (r Render) Describe(name string) (TemplateInfo, bool) {
+	tmpl, ok := r[name]
+	if !ok {
+		return TemplateInfo{}, false
+	}
+	if v, ok := renderInfo.Load(weak.Make(tmpl)); ok {
+		info := v.(TemplateInfo)
+		info.Name = name
+		return info, true
+	}
+	return newTemplateBuilder(name, tmpl).describe(tmpl), true
+}
+
+// Names returns the registered template names, sorted
+func (r DynamicRender) Names() []string {
+	return slices.Sorted(maps.Keys(r))
+}
+
+// Has reports whether a template is registered under name
+func (r DynamicRender) Has(name string) bool {
+	_, ok := r[name]
+	return ok
+}
+
+// Describe reports how the template registered under name is loaded,
+// rebuilding it first when its files changed
+func (r DynamicRender) Describe(name string) (TemplateInfo, bool) {
+	b, ok := r[name]
+	if !ok {
+		return TemplateInfo{}, false
+	}
+	tmpl, _ := b.template()
+	return b.describe(tmpl), true
+}
+
+// Names returns the registered template names, sorted
+func (r *SyncRender) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Sorted(maps.Keys(r.builders))
+}
+
+// Has reports whether a template is registered under name
+func (r *SyncRender) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.builders[name]
+	return ok
+}
+
+// Describe reports how the template registered under name is loaded
+func (r *SyncRender) Describe(name string) (TemplateInfo, bool) {
+	r.mu.RLock()
+	b, ok := r.builders[name]
+	r.mu.RUnlock()
+	if !ok {
+		return TemplateInfo{}, false
+	}
+	tmpl := b.current()
+	if r.dynamic {
+		tmpl, _ = b.template()
+	}
+	return b.describe(tmpl), true
+}
+
+// Names returns the registered template names, sorted
+func (r *TextRender) Names() []string {
+	return slices.Sorted(maps.Keys(r.templates))
+}
+
+// Has reports whether a template is registered under name
+func (r *TextRender) Has(name string) bool {
+	_, ok := r.templates[name]
+	return ok
+}
@@ -0,0 +1,95 @@
+package multitemplate
+
+import (
+	"html/template"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+	"weak"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribe(t *testing.T) {
+	for name, r := range htmlRenderers() {
+		t.Run(name, func(t *testing.T) {
+			r.AddFromFilesFuncsWithOptions("files", template.FuncMap{"b": strings.ToUpper, "a": strings.ToLower},
+				*NewTemplateOptions(WithLeftDelimiter("[["), WithRightDelimiter("]]")),
+				"tests/blocks/users.html")
+			r.AddFromGlob("glob", "tests/global/*")
+			r.AddFromFS("fs", os.DirFS("tests"), "global/*")
+			r.AddFromString("strings", `{{ define "x" }}{{ end }}`)
+			r.Add("template", template.Must(template.New("t").Parse(`{{ define "y" }}{{ end }}`)))
+
+			assert.Equal(t, []string{"files", "fs", "glob", "strings", "template"}, r.Names())
+			assert.True(t, r.Has("glob"))
+			assert.False(t, r.Has("missing"))
+			_, ok := r.Describe("missing")
+			assert.False(t, ok)
+
+			info, ok := r.Describe("files")
+			assert.True(t, ok)
+			assert.Equal(t, TemplateInfo{
+				Name:           "files",
+				Loader:         LoaderFiles,
+				Files:          []string{"tests/blocks/users.html"},
+				LeftDelimiter:  "[[",
+				RightDelimiter: "]]",
+				Funcs:          []string{"a", "b"},
+			}, info)
+
+			info, _ = r.Describe("glob")
+			assert.Equal(t, LoaderGlob, info.Loader)
+			assert.Equal(t, []string{"tests/global/*"}, info.Patterns)
+			assert.Equal(t, []string{"tests/global/base.html", "tests/global/login.html"}, info.Files)
+			assert.Equal(t, []string{"login.html"}, info.Blocks)
+
+			info, _ = r.Describe("fs")
+			assert.Equal(t, LoaderFS, info.Loader)
+			assert.Equal(t, []string{"global/*"}, info.Patterns)
+			assert.Equal(t, []string{"global/base.html", "global/login.html"}, info.Files)
+
+			info, _ = r.Describe("strings")
+			assert.Equal(t, LoaderStrings, info.Loader)
+			assert.Equal(t, []string{"x"}, info.Blocks)
+			assert.Equal(t, "{{", info.LeftDelimiter)
+
+			info, _ = r.Describe("template")
+			assert.Equal(t, TemplateInfo{Name: "template", Loader: LoaderTemplate, Blocks: []string{"y"},
+				LeftDelimiter: "{{", RightDelimiter: "}}"}, info)
+		})
+	}
+}
+
+func TestDescribeUsersBlocks(t *testing.T) {
+	r := New()
+	r.AddFromFiles("users", "tests/blocks/users.html")
+	info, _ := r.Describe("users")
+	assert.Equal(t, []string{"row"}, info.Blocks)
+}
+
+func TestRenderInfoIsCollected(t *testing.T) {
+	r := New()
+	r.AddFromString("index", "index")
+	key := weak.Make(r["index"])
+	_, ok := renderInfo.Load(key)
+	assert.True(t, ok)
+
+	delete(r, "index")
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+		_, ok := renderInfo.Load(key)
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTextNames(t *testing.T) {
+	r := NewText("")
+	r.AddFromString("b", "b")
+	r.AddFromString("a", "a")
+	assert.Equal(t, []string{"a", "b"}, r.Names())
+	assert.True(t, r.Has("a"))
+	assert.False(t, r.Has("c"))
+}
@@ -0,0 +1,310 @@
+package multitemplate
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"text/template/parse"
+)
+
+// Severity tells whether a LintIssue is an error or a warning
+type Severity string
+
+// Severities of lint issues
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// LintIssue is a problem found by Lint
+type LintIssue struct {
+	Severity Severity
+	// Template is the name of the template set the issue was found in, empty
+	// for issues about a file regardless of the set
+	Template string
+	File     string
+	Line     int
+	Column   int
+	Message  string
+}
+
+func (i LintIssue) String() string {
+	var b strings.Builder
+	b.WriteString(i.File)
+	if i.Line > 0 {
+		fmt.Fprintf(&b, ":%d", i.Line)
+		if i.Column > 0 {
+			fmt.Fprintf(&b, ":%d", i.Column)
+		}
+	}
+	fmt.Fprintf(&b, ": %s: %s", i.Severity, i.Message)
+	if i.Template != "" {
+		fmt.Fprintf(&b, " (template %q)", i.Template)
+	}
+	return b.String()
+}
+
+// Lint composes the template sets of fsys the way LoadFS does and checks
+// them without executing anything. It reports as errors:
+//   - parse errors, including calls to functions missing from LoadConfig.Funcs
+//   - {{ template }} calls to templates not defined in the set
+//   - templates defined by more than one file of a set, where the later
+//     definition silently wins; overriding a {{ block }} is fine
+//
+// and as warnings templates that are defined but never called by any set.
+// Such blocks may still be rendered with InstanceBlock. The returned error
+// only reports a failure to read fsys.
+func Lint(fsys fs.FS, configs ...LoadConfig) ([]LintIssue, error) {
+	sets, err := planDirectory(fsys, configs)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &linter{fsys: fsys, files: make(map[lintKey]*lintFile)}
+	for _, set := range sets {
+		if err := l.lintSet(set); err != nil {
+			return nil, err
+		}
+	}
+	l.lintUnused()
+
+	slices.SortFunc(l.issues, func(a, b LintIssue) int {
+		return cmp.Or(
+			cmp.Compare(a.File, b.File),
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Column, b.Column),
+			cmp.Compare(a.Message, b.Message),
+			cmp.Compare(a.Template, b.Template),
+		)
+	})
+	return slices.Compact(l.issues), nil
+}
+
+// lintKey identifies a file parsed with a pair of delimiters
+type lintKey struct {
+	file        string
+	left, right string
+}
+
+// lintFile is a source file parsed on its own
+type lintFile struct {
+	path  string
+	text  string
+	trees map[string]*parse.Tree // nil when the file does not parse
+	calls map[string]bool        // templates called from the file
+}
+
+type linter struct {
+	fsys   fs.FS
+	files  map[lintKey]*lintFile
+	issues []LintIssue
+}
+
+func (l *linter) report(severity Severity, set string, f *lintFile, pos parse.Pos, format string, args ...any) {
+	line, column := f.position(pos)
+	l.issues = append(l.issues, LintIssue{
+		Severity: severity,
+		Template: set,
+		File:     f.path,
+		Line:     line,
+		Column:   column,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// lintSet parses the set as a whole like LoadFS, then checks the calls and
+// definitions of its files
+func (l *linter) lintSet(set templateSet) error {
+	options := set.config.Options
+	newTemplate := func(name string) *template.Template {
+		return template.New(name).
+			Delims(options.LeftDelimiter, options.RightDelimiter).
+			Option(options.Options...).
+			Funcs(set.config.Funcs)
+	}
+	if _, _, err := parseFiles(newTemplate, l.fsys, set.files); err != nil {
+		var te *TemplateError
+		if !errors.As(err, &te) {
+			return err
+		}
+		l.issues = append(l.issues, LintIssue{
+			Severity: SeverityError,
+			Template: set.name,
+			File:     te.File,
+			Line:     te.Line,
+			Column:   te.Column,
+			Message:  strings.TrimSpace(positionPattern.ReplaceAllString(te.Err.Error(), "")),
+		})
+		return nil
+	}
+
+	files := make([]*lintFile, len(set.files))
+	defined := make(map[string]bool)
+	for i, file := range set.files {
+		f, err := l.parse(file, options)
+		if err != nil {
+			return err
+		}
+		if f.trees == nil {
+			return nil
+		}
+		files[i] = f
+		for name := range f.trees {
+			defined[name] = true
+		}
+	}
+
+	definedBy := make(map[string][]*lintFile)
+	for _, f := range files {
+		for _, name := range slices.Sorted(maps.Keys(f.trees)) {
+			tree := f.trees[name]
+			walkTemplateCalls(tree.Root, func(n *parse.TemplateNode) {
+				if !defined[n.Name] {
+					l.report(SeverityError, set.name, f, n.Position(), "template %q is not defined", n.Name)
+				}
+			})
+			if name != f.root() && !f.calls[name] {
+				definedBy[name] = append(definedBy[name], f)
+			}
+		}
+	}
+	for name, defs := range definedBy {
+		for _, f := range defs[1:] {
+			l.report(SeverityError, set.name, f, f.trees[name].Root.Position(),
+				"template %q is also defined in %s", name, defs[0].path)
+		}
+	}
+	return nil
+}
+
+// lintUnused reports the templates defined in a file that no set calls
+func (l *linter) lintUnused() {
+	called := make(map[string]bool)
+	for _, f := range l.files {
+		for name := range f.calls {
+			called[name] = true
+		}
+	}
+	for _, f := range l.files {
+		for name, tree := range f.trees {
+			if name != f.root() && !called[name] {
+				l.report(SeverityWarning, "", f, tree.Root.Position(), "template %q is never called", name)
+			}
+		}
+	}
+}
+
+// parse parses file on its own, without checking function names, once per
+// pair of delimiters. trees is nil when it does not parse: the error is
+// reported by the parse of the set.
+func (l *linter) parse(file string, options TemplateOptions) (*lintFile, error) {
+	key := lintKey{file: file, left: options.LeftDelimiter, right: options.RightDelimiter}
+	if f, ok := l.files[key]; ok {
+		return f, nil
+	}
+	b, err := fs.ReadFile(l.fsys, file)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &lintFile{path: file, text: string(b), calls: make(map[string]bool)}
+	l.files[key] = f
+	t := parse.New(f.root())
+	t.Mode = parse.SkipFuncCheck
+	trees := make(map[string]*parse.Tree)
+	if _, err := t.Parse(f.text, options.LeftDelimiter, options.RightDelimiter, trees); err != nil {
+		return f, nil // reported by the parse of the set
+	}
+	f.trees = trees
+	for _, tree := range trees {
+		walkTemplateCalls(tree.Root, func(n *parse.TemplateNode) {
+			f.calls[n.Name] = true
+		})
+	}
+	return f, nil
+}
+
+// root is the name of the template holding the text of the file outside of definitions
+func (f *lintFile) root() string {
+	return path.Base(f.path)
+}
+
+// position converts a byte offset into a 1-based line and column
+func (f *lintFile) position(pos parse.Pos) (int, int) {
+	text := f.text[:min(int(pos), len(f.text))]
+	line := strings.Count(text, "\n") + 1
+	column := len(text) - strings.LastIndex(text, "\n")
+	return line, column
+}
+
+// walkTemplateCalls calls fn for every {{ template }} action under node
+func walkTemplateCalls(node parse.Node, fn func(*parse.TemplateNode)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkTemplateCalls(child, fn)
+		}
+	case *parse.IfNode:
+		walkTemplateCalls(n.List, fn)
+		walkTemplateCalls(n.ElseList, fn)
+	case *parse.RangeNode:
+		walkTemplateCalls(n.List, fn)
+		walkTemplateCalls(n.ElseList, fn)
+	case *parse.WithNode:
+		walkTemplateCalls(n.List, fn)
+		walkTemplateCalls(n.ElseList, fn)
+	case *parse.TemplateNode:
+		fn(n)
+	}
+}
+
+// walkCommands calls fn for every command under node, including the commands
+// of nested pipelines
+func walkCommands(node parse.Node, fn func(*parse.CommandNode)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkCommands(child, fn)
+		}
+	case *parse.ActionNode:
+		walkCommands(n.Pipe, fn)
+	case *parse.IfNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.RangeNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.WithNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.TemplateNode:
+		walkCommands(n.Pipe, fn)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			fn(cmd)
+			for _, arg := range cmd.Args {
+				walkCommands(arg, fn)
+			}
+		}
+	case *parse.ChainNode:
+		walkCommands(n.Node, fn)
+	}
+}
+
+func walkBranch(n *parse.BranchNode, fn func(*parse.CommandNode)) {
+	walkCommands(n.Pipe, fn)
+	walkCommands(n.List, fn)
+	walkCommands(n.ElseList, fn)
+}
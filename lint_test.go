@@ -0,0 +1,62 @@
+package multitemplate
+
+import (
+	"html/template"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var lintConfig = LoadConfig{
+	Layouts:  "layouts/*.html",
+	Partials: "partials/*.html",
+	Pages:    "pages",
+	Funcs:    template.FuncMap{"upper": strings.ToUpper},
+	Options:  *NewTemplateOptions(),
+}
+
+func TestLint(t *testing.T) {
+	issues, err := Lint(os.DirFS("tests/lint"), lintConfig)
+	assert.NoError(t, err)
+
+	lines := make([]string, len(issues))
+	for i, issue := range issues {
+		lines[i] = issue.String()
+	}
+	assert.Equal(t, []string{
+		`pages/about.html:2:22: error: template "footer" is also defined in partials/nav.html (template "about.html")`,
+		`pages/broken.html:4: error: unexpected "{" in end (template "broken.html")`,
+		`partials/nav.html:1:36: error: template "menu" is not defined (template "about.html")`,
+		`partials/nav.html:1:36: error: template "menu" is not defined (template "index.html")`,
+		`partials/nav.html:3:22: warning: template "unused" is never called`,
+	}, lines)
+	assert.Equal(t, SeverityError, issues[0].Severity)
+	assert.Equal(t, "about.html", issues[0].Template)
+}
+
+func TestLintFuncs(t *testing.T) {
+	config := lintConfig
+	config.Funcs = nil
+	issues, err := Lint(os.DirFS("tests/lint"), config)
+	assert.NoError(t, err)
+	assert.Contains(t, issues, LintIssue{
+		Severity: SeverityError,
+		Template: "index.html",
+		File:     "pages/index.html",
+		Line:     1,
+		Message:  `function "upper" not defined`,
+	})
+}
+
+func TestLintClean(t *testing.T) {
+	issues, err := Lint(os.DirFS("tests/site"), siteConfigs...)
+	assert.NoError(t, err)
+	for _, issue := range issues {
+		assert.Equal(t, SeverityWarning, issue.Severity, issue.String())
+	}
+
+	_, err = Lint(os.DirFS("tests/lint"), LoadConfig{Pages: "missing"})
+	assert.Error(t, err)
+}
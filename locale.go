@@ -0,0 +1,197 @@
+package multitemplate
+
+import (
+	"cmp"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// localeKey is the key of the locale chain in the gin.Context
+const localeKey = "multitemplate.locale"
+
+// LocaleConfig configures the Localize middleware
+type LocaleConfig struct {
+	// Locales are the supported locales, e.g. "en", "de", "de-AT". Requested
+	// locales are matched against them, by language when there is no exact
+	// match. Any locale is accepted when empty.
+	Locales []string
+	// Default is the last locale of every fallback chain, e.g. "en"
+	Default string
+	// Param is the name of the route or query parameter selecting the
+	// locale, e.g. "lang". It takes precedence over Cookie.
+	Param string
+	// Cookie is the name of the cookie selecting the locale. It takes
+	// precedence over the Accept-Language header.
+	Cookie string
+	// Fallbacks are the locales tried after a locale, e.g. "de-CH": {"de", "fr"}.
+	// A locale without fallbacks falls back to its language, "de-AT" to "de".
+	Fallbacks map[string][]string
+}
+
+// Localize returns a middleware that selects the locale of each request, from
+// the route or query parameter, the cookie or the Accept-Language header in
+// that order, and makes c.HTML render the locale variant of a template name.
+// The variant of "index.html" for "de" is "index.de.html", and of "index" is
+// "index.de". Variants are tried along the fallback chain of the locale, e.g.
+// "de-AT", "de" then the default locale, before the name itself.
+func Localize(config LocaleConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		if config.Cookie != "" {
+			c.Writer.Header().Add("Vary", "Cookie")
+		}
+		locales := config.chain(config.requested(c))
+		if len(locales) == 0 {
+			c.Next()
+			return
+		}
+
+		c.Set(localeKey, locales)
+		nextWithContext(c)
+	}
+}
+
+// Locale returns the locale selected for the request by Localize, empty
+// if there is none
+func Locale(c *gin.Context) string {
+	if locales := c.GetStringSlice(localeKey); len(locales) > 0 {
+		return locales[0]
+	}
+	return ""
+}
+
+// requested returns the supported locale selected by the request, or the
+// default locale
+func (config LocaleConfig) requested(c *gin.Context) string {
+	var candidates []string
+	if config.Param != "" {
+		candidates = append(candidates, c.Param(config.Param), c.Query(config.Param))
+	}
+	if config.Cookie != "" {
+		if cookie, err := c.Cookie(config.Cookie); err == nil {
+			candidates = append(candidates, cookie)
+		}
+	}
+	candidates = append(candidates, parseAcceptLanguage(c.GetHeader("Accept-Language"))...)
+	for _, candidate := range candidates {
+		if locale := config.match(candidate); locale != "" {
+			return locale
+		}
+	}
+	return config.Default
+}
+
+// match returns the supported locale for the tag, empty if none
+func (config LocaleConfig) match(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return ""
+	}
+	if len(config.Locales) == 0 {
+		return tag
+	}
+	if i := slices.IndexFunc(config.Locales, func(l string) bool { return strings.EqualFold(l, tag) }); i >= 0 {
+		return config.Locales[i]
+	}
+	language := localeLanguage(tag)
+	for _, l := range config.Locales {
+		if strings.EqualFold(l, language) {
+			return l
+		}
+	}
+	for _, l := range config.Locales {
+		if strings.EqualFold(localeLanguage(l), language) {
+			return l
+		}
+	}
+	return ""
+}
+
+// chain returns the locales tried for locale, in order
+func (config LocaleConfig) chain(locale string) []string {
+	if locale == "" {
+		return nil
+	}
+	chain := []string{locale}
+	if fallbacks, ok := config.Fallbacks[locale]; ok {
+		chain = append(chain, fallbacks...)
+	} else if language := localeLanguage(locale); language != locale {
+		chain = append(chain, language)
+	}
+	if config.Default != "" {
+		chain = append(chain, config.Default)
+	}
+
+	var unique []string
+	for _, l := range chain {
+		if !slices.Contains(unique, l) {
+			unique = append(unique, l)
+		}
+	}
+	return unique
+}
+
+// localeLanguage returns the language of a locale, "de" for "de-AT"
+func localeLanguage(locale string) string {
+	language, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	return language
+}
+
+// parseAcceptLanguage returns the tags of an Accept-Language header, by
+// decreasing quality
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for part := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				q = 0
+			}
+		}
+		if tag != "" && tag != "*" && q > 0 {
+			tags = append(tags, weighted{tag: tag, q: q})
+		}
+	}
+	slices.SortStableFunc(tags, func(a, b weighted) int {
+		return cmp.Compare(b.q, a.q)
+	})
+
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
+
+// localeName returns the variant of the template name for locale
+func localeName(name, locale string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + locale + ext
+}
+
+// localize returns the first variant of name registered in r along the locale
+// chain selected by the Localize middleware for w, or name itself
+func localize(r blockRenderer, name string, w http.ResponseWriter) string {
+	c, ok := requestContext(w)
+	if !ok {
+		return name
+	}
+	for _, locale := range c.GetStringSlice(localeKey) {
+		if variant := localeName(name, locale); r.Has(variant) {
+			return variant
+		}
+	}
+	return name
+}
@@ -0,0 +1,91 @@
+package multitemplate
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalize(t *testing.T) {
+	r := New()
+	assert.NoError(t, r.LoadDirectory("tests/locales", LoadConfig{Layouts: "layout.html", Pages: "pages"}))
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.Use(Localize(LocaleConfig{
+		Locales:   []string{"en", "de", "de-AT", "fr", "de-CH"},
+		Default:   "en",
+		Param:     "lang",
+		Cookie:    "lang",
+		Fallbacks: map[string][]string{"de-CH": {"de-AT"}},
+	}), Partials("content"))
+	router.GET("/:name", func(c *gin.Context) {
+		c.HTML(http.StatusOK, c.Param("name"), Locale(c))
+	})
+
+	for _, tc := range []struct {
+		name     string
+		path     string
+		header   http.Header
+		expected string
+	}{
+		{"default", "/index.html", http.Header{}, "<html>Hello en</html>\n"},
+		{"header", "/index.html", http.Header{"Accept-Language": {"fr;q=0.5, de;q=0.9"}}, "<html>Hallo de</html>\n"},
+		{"region", "/index.html", http.Header{"Accept-Language": {"de-at"}}, "<html>Servus de-AT</html>\n"},
+		{"language fallback", "/index.html", http.Header{"Accept-Language": {"de-DE"}}, "<html>Hallo de</html>\n"},
+		{"configured fallback", "/index.html", http.Header{"Accept-Language": {"de-CH"}}, "<html>Servus de-CH</html>\n"},
+		{"default fallback", "/index.html", http.Header{"Accept-Language": {"fr"}}, "<html>Hello fr</html>\n"},
+		{"unsupported", "/index.html", http.Header{"Accept-Language": {"ja"}}, "<html>Hello en</html>\n"},
+		{"cookie", "/index.html", http.Header{"Cookie": {"lang=de"}, "Accept-Language": {"fr"}}, "<html>Hallo de</html>\n"},
+		{"param", "/index.html?lang=de-AT", http.Header{"Cookie": {"lang=de"}}, "<html>Servus de-AT</html>\n"},
+		{"no variant", "/about.html", http.Header{"Accept-Language": {"de"}}, "<html>About de</html>\n"},
+		{"partial", "/index.html", http.Header{"Accept-Language": {"de"}, "Hx-Request": {"true"}}, "Hallo de"},
+		{"block", "/index.html%23content", http.Header{"Accept-Language": {"de"}}, "Hallo de"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := performRequestHeader(router, tc.path, tc.header)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tc.expected, w.Body.String())
+		})
+	}
+
+	w := performRequestHeader(router, "/index.html", http.Header{})
+	assert.Equal(t, []string{"Accept-Language", "Cookie", "HX-Request", "Turbo-Frame"}, w.Header().Values("Vary"))
+}
+
+func TestLocalizeDataProviders(t *testing.T) {
+	r := NewSync(WithTemplateDataProvider("index.html", func(*gin.Context) (gin.H, error) {
+		return gin.H{"user": "bob"}, nil
+	}))
+	r.AddFromString("index.html", `en {{ .user }}`)
+	r.AddFromString("index.de.html", `de {{ .user }}`)
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.Use(DataProviders(), Localize(LocaleConfig{Default: "en"}))
+	router.GET("/", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "index.html", nil)
+	})
+
+	w := performRequestHeader(router, "/", http.Header{"Accept-Language": {"de"}})
+	assert.Equal(t, "de bob", w.Body.String())
+	w = performRequestHeader(router, "/", http.Header{"Accept-Language": {"fr"}})
+	assert.Equal(t, "en bob", w.Body.String())
+}
+
+func TestLocaleChain(t *testing.T) {
+	config := LocaleConfig{Default: "en"}
+	assert.Equal(t, []string{"de-AT", "de", "en"}, config.chain("de-AT"))
+	assert.Equal(t, []string{"en"}, config.chain("en"))
+	assert.Nil(t, LocaleConfig{}.chain(""))
+
+	assert.Equal(t, []string{"da", "en-gb", "en"}, parseAcceptLanguage("da, en-gb;q=0.8, en;q=0.7, *;q=0.1, fr;q=0"))
+	assert.Equal(t, []string{"en-GB"}, parseAcceptLanguage("en-GB"))
+	assert.Empty(t, parseAcceptLanguage(""))
+
+	assert.Equal(t, "index.de.html", localeName("index.html", "de"))
+	assert.Equal(t, "admin/users.de-AT.html", localeName("admin/users.html", "de-AT"))
+	assert.Equal(t, "index.de", localeName("index", "de"))
+}
@@ -0,0 +1,112 @@
+package multitemplate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin/render"
+)
+
+// maxSuggestions is the number of similar names offered for an unknown template
+const maxSuggestions = 3
+
+// MissingTemplateFunc decides what Instance renders for a template name that is
+// not registered. err describes the problem, including similar registered names.
+type MissingTemplateFunc func(name string, data any, err error) render.Render
+
+// WithMissingTemplate calls fn to render unknown template names instead of
+// responding with a 500 error. Like every Option it applies to a SyncRender;
+// Render and DynamicRender always respond with a 500 error.
+func WithMissingTemplate(fn MissingTemplateFunc) Option {
+	return func(r *SyncRender) {
+		r.missing = fn
+	}
+}
+
+// WithFallbackTemplate renders the template registered under fallback, with
+// the data of the original call, for unknown template names. The status code
+// chosen by the handler is kept. If the fallback is not registered either,
+// the default 500 error is rendered.
+func WithFallbackTemplate(fallback string) Option {
+	return func(r *SyncRender) {
+		r.missing = func(_ string, data any, err error) render.Render {
+			if _, ok, _ := r.template(fallback); !ok {
+				return internalError{err: err}
+			}
+			return r.Instance(fallback, data)
+		}
+	}
+}
+
+// notFoundError reports that name is not registered, suggesting similar names
+func notFoundError(name string, names []string) error {
+	return &TemplateError{Name: name, Err: withSuggestions(ErrTemplateNotFound, name, names)}
+}
+
+// withSuggestions appends the names close to name to the message of err
+func withSuggestions(err error, name string, names []string) error {
+	suggestions := suggest(name, names)
+	if len(suggestions) == 0 {
+		return err
+	}
+	quoted := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return fmt.Errorf("%w, did you mean %s?", err, strings.Join(quoted, " or "))
+}
+
+// suggest returns up to maxSuggestions names close to name, closest first.
+// A name is close when its edit distance to name is at most a third of the
+// length of name (and at least 2), or when one contains the other.
+func suggest(name string, names []string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	limit := max(len(name)/3, 2)
+	lower := strings.ToLower(name)
+	var candidates []candidate
+	for _, n := range names {
+		d := levenshtein(lower, strings.ToLower(n))
+		if d <= limit || strings.Contains(n, name) || strings.Contains(name, n) {
+			candidates = append(candidates, candidate{name: n, distance: d})
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	suggestions := make([]string, 0, maxSuggestions)
+	for _, c := range candidates[:min(len(candidates), maxSuggestions)] {
+		suggestions = append(suggestions, c.name)
+	}
+	return suggestions
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,81 @@
+package multitemplate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingTemplate(t *testing.T) {
+	r := make(Render)
+	r.AddFromString("index", "index")
+	r.AddFromString("admin/users", "users")
+
+	w := httptest.NewRecorder()
+	err := r.Instance("admin/user", nil).Render(w)
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+	assert.EqualError(t, err, `multitemplate: template "admin/user": template not found, did you mean "admin/users"?`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, err.Error(), w.Body.String())
+
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(gin.DebugMode)
+	w = httptest.NewRecorder()
+	assert.Error(t, r.Instance("unknown", nil).Render(w))
+	assert.Equal(t, "Internal Server Error", w.Body.String(), "the error is not leaked in release mode")
+
+	_, err = r.RenderString("inde", nil)
+	assert.EqualError(t, err, `multitemplate: template "inde": template not found, did you mean "index"?`)
+}
+
+func TestMissingTemplateFallback(t *testing.T) {
+	r := NewSync(WithFallbackTemplate("404"))
+	r.AddFromString("index", "index")
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.GET("/", func(c *gin.Context) {
+		c.HTML(http.StatusNotFound, "unknown", "data")
+	})
+
+	w := performRequest(router)
+	assert.Equal(t, http.StatusInternalServerError, w.Code, "the fallback is not registered yet")
+
+	r.AddFromString("404", "missing {{ . }}")
+	w = performRequest(router)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "missing data", w.Body.String())
+}
+
+func TestMissingTemplateHook(t *testing.T) {
+	var missing string
+	r := NewSync(WithMissingTemplate(func(name string, data any, err error) render.Render {
+		missing = name
+		assert.ErrorIs(t, err, ErrTemplateNotFound)
+		return render.String{Format: "no %s", Data: []any{name}}
+	}))
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.GET("/", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "index", nil)
+	})
+
+	w := performRequest(router)
+	assert.Equal(t, "index", missing)
+	assert.Equal(t, "no index", w.Body.String())
+}
+
+func TestSuggest(t *testing.T) {
+	names := []string{"index.html", "admin/users.html", "admin/groups.html", "about.html"}
+	assert.Equal(t, []string{"index.html"}, suggest("Index.html", names))
+	assert.Equal(t, []string{"admin/users.html"}, suggest("admin/user.html", names))
+	assert.Equal(t, []string{"admin/users.html"}, suggest("users", names))
+	assert.Empty(t, suggest("contact.html", names))
+	assert.Empty(t, suggest("x", nil))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+}
@@ -1,20 +1,24 @@
 package multitemplate
 
 import (
-	"fmt"
 	"html/template"
 	"io/fs"
-	"path/filepath"
 
 	"github.com/gin-gonic/gin/render"
 )
 
 // Render type
 type (
+	// Render holds templates parsed once. An unknown template name always
+	// renders a 500 error: WithMissingTemplate and WithFallbackTemplate
+	// require a SyncRender, created with NewSync or NewRenderer(opts...).
 	Render          map[string]*template.Template
 	TemplateOptions struct {
 		LeftDelimiter  string
 		RightDelimiter string
+		// Options are html/template options such as "missingkey=error",
+		// see template.Option
+		Options []string
 	}
 )
 
@@ -39,6 +43,20 @@ func Delims(leftDelim, rightDelim string) TemplateOption {
 	}
 }
 
+// WithOption adds html/template options such as "missingkey=zero", see template.Option
+func WithOption(options ...string) TemplateOption {
+	return func(t *TemplateOptions) {
+		t.Options = append(t.Options, options...)
+	}
+}
+
+// WithMissingKey sets what executing a template does when a map has no entry
+// for a key: "default" or "invalid" print nothing, "zero" the zero value and
+// "error" stops with an error
+func WithMissingKey(value string) TemplateOption {
+	return WithOption("missingkey=" + value)
+}
+
 func NewTemplateOptions(opts ...TemplateOption) *TemplateOptions {
 	const (
 		defaultLeftDelim  = "{{"
@@ -69,52 +87,34 @@ func New() Render {
 
 // Add new template
 func (r Render) Add(name string, tmpl *template.Template) {
-	if tmpl == nil {
-		panic("template can not be nil")
+	if err := r.TryAdd(name, tmpl); err != nil {
+		panic(err)
 	}
-	if len(name) == 0 {
-		panic("template name cannot be empty")
-	}
-	if _, ok := r[name]; ok {
-		panic(fmt.Sprintf("template %s already exists", name))
-	}
-	r[name] = tmpl
 }
 
 // AddFromFiles supply add template from files
 func (r Render) AddFromFiles(name string, files ...string) *template.Template {
-	tmpl := template.Must(template.ParseFiles(files...))
-	r.Add(name, tmpl)
-	return tmpl
+	return mustTemplate(r.TryAddFromFiles(name, files...))
 }
 
 // AddFromGlob supply add template from global path
 func (r Render) AddFromGlob(name, glob string) *template.Template {
-	tmpl := template.Must(template.ParseGlob(glob))
-	r.Add(name, tmpl)
-	return tmpl
+	return mustTemplate(r.TryAddFromGlob(name, glob))
 }
 
 // AddFromFS supply add template from fs.FS (e.g. embed.FS)
 func (r Render) AddFromFS(name string, fsys fs.FS, files ...string) *template.Template {
-	tmpl := template.Must(template.ParseFS(fsys, files...))
-	r.Add(name, tmpl)
-	return tmpl
+	return mustTemplate(r.TryAddFromFS(name, fsys, files...))
 }
 
 // AddFromFSFuncs supply add template from fs.FS (e.g. embed.FS) with callback func
 func (r Render) AddFromFSFuncs(name string, funcMap template.FuncMap, fsys fs.FS, files ...string) *template.Template {
-	tname := filepath.Base(files[0])
-	tmpl := template.Must(template.New(tname).Funcs(funcMap).ParseFS(fsys, files...))
-	r.Add(name, tmpl)
-	return tmpl
+	return mustTemplate(r.TryAddFromFSFuncs(name, funcMap, fsys, files...))
 }
 
 // AddFromString supply add template from strings
 func (r Render) AddFromString(name, templateString string) *template.Template {
-	tmpl := template.Must(template.New(name).Parse(templateString))
-	r.Add(name, tmpl)
-	return tmpl
+	return mustTemplate(r.TryAddFromString(name, templateString))
 }
 
 // AddFromStringsFuncs supply add template from strings
@@ -123,14 +123,7 @@ func (r Render) AddFromStringsFuncs(
 	funcMap template.FuncMap,
 	templateStrings ...string,
 ) *template.Template {
-	tmpl := template.New(name).Funcs(funcMap)
-
-	for _, ts := range templateStrings {
-		tmpl = template.Must(tmpl.Parse(ts))
-	}
-
-	r.Add(name, tmpl)
-	return tmpl
+	return mustTemplate(r.TryAddFromStringsFuncs(name, funcMap, templateStrings...))
 }
 
 // AddFromStringsFuncsWithOptions supply add template from strings with options
@@ -140,26 +133,12 @@ func (r Render) AddFromStringsFuncsWithOptions(
 	options TemplateOptions,
 	templateStrings ...string,
 ) *template.Template {
-	tmpl := template.New(name).
-		Delims(options.LeftDelimiter, options.RightDelimiter).
-		Funcs(funcMap)
-
-	for _, ts := range templateStrings {
-		tmpl = template.Must(
-			tmpl.Parse(ts),
-		).Delims(options.LeftDelimiter, options.RightDelimiter)
-	}
-
-	r.Add(name, tmpl)
-	return tmpl
+	return mustTemplate(r.TryAddFromStringsFuncsWithOptions(name, funcMap, options, templateStrings...))
 }
 
 // AddFromFilesFuncs supply add template from file callback func
 func (r Render) AddFromFilesFuncs(name string, funcMap template.FuncMap, files ...string) *template.Template {
-	tname := filepath.Base(files[0])
-	tmpl := template.Must(template.New(tname).Funcs(funcMap).ParseFiles(files...))
-	r.Add(name, tmpl)
-	return tmpl
+	return mustTemplate(r.TryAddFromFilesFuncs(name, funcMap, files...))
 }
 
 // AddFromFilesFuncsWithOptions supply add template from file callback func with options
@@ -169,21 +148,179 @@ func (r Render) AddFromFilesFuncsWithOptions(
 	options TemplateOptions,
 	files ...string,
 ) *template.Template {
-	tname := filepath.Base(files[0])
-	tmpl := template.Must(
-		template.New(tname).
-			Delims(options.LeftDelimiter, options.RightDelimiter).
-			Funcs(funcMap).
-			ParseFiles(files...),
-	)
-	r.Add(name, tmpl)
+	return mustTemplate(r.TryAddFromFilesFuncsWithOptions(name, funcMap, options, files...))
+}
+
+// AddFromGlobFuncsWithOptions supply add template from global path with callback func and options
+func (r Render) AddFromGlobFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	glob string,
+) *template.Template {
+	return mustTemplate(r.TryAddFromGlobFuncsWithOptions(name, funcMap, options, glob))
+}
+
+// AddFromFSFuncsWithOptions supply add template from fs.FS (e.g. embed.FS) with callback func and options
+func (r Render) AddFromFSFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	fsys fs.FS,
+	files ...string,
+) *template.Template {
+	return mustTemplate(r.TryAddFromFSFuncsWithOptions(name, funcMap, options, fsys, files...))
+}
+
+// TryAdd is like Add but returns an error instead of panicking
+func (r Render) TryAdd(name string, tmpl *template.Template) error {
+	if tmpl == nil {
+		return &TemplateError{Name: name, Err: ErrNilTemplate}
+	}
+	if len(name) == 0 {
+		return &TemplateError{Err: ErrEmptyName}
+	}
+	if _, ok := r[name]; ok {
+		return &TemplateError{Name: name, Err: ErrTemplateExists}
+	}
+	r[name] = tmpl
+	return nil
+}
+
+// TryAddFromFiles is like AddFromFiles but returns an error instead of panicking
+func (r Render) TryAddFromFiles(name string, files ...string) (*template.Template, error) {
+	return r.addBuilder(newFilesBuilder(name, nil, *NewTemplateOptions(), files))
+}
+
+// TryAddFromGlob is like AddFromGlob but returns an error instead of panicking
+func (r Render) TryAddFromGlob(name, glob string) (*template.Template, error) {
+	return r.addBuilder(newGlobBuilder(name, nil, *NewTemplateOptions(), glob))
+}
+
+// TryAddFromFS is like AddFromFS but returns an error instead of panicking
+func (r Render) TryAddFromFS(name string, fsys fs.FS, files ...string) (*template.Template, error) {
+	return r.addBuilder(newFSBuilder(name, nil, *NewTemplateOptions(), fsys, files))
+}
+
+// TryAddFromFSFuncs is like AddFromFSFuncs but returns an error instead of panicking
+func (r Render) TryAddFromFSFuncs(
+	name string,
+	funcMap template.FuncMap,
+	fsys fs.FS,
+	files ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newFSBuilder(name, funcMap, *NewTemplateOptions(), fsys, files))
+}
+
+// TryAddFromString is like AddFromString but returns an error instead of panicking
+func (r Render) TryAddFromString(name, templateString string) (*template.Template, error) {
+	return r.addBuilder(newStringsBuilder(name, nil, *NewTemplateOptions(), []string{templateString}))
+}
+
+// TryAddFromStringsFuncs is like AddFromStringsFuncs but returns an error instead of panicking
+func (r Render) TryAddFromStringsFuncs(
+	name string,
+	funcMap template.FuncMap,
+	templateStrings ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newStringsBuilder(name, funcMap, *NewTemplateOptions(), templateStrings))
+}
+
+// TryAddFromStringsFuncsWithOptions is like AddFromStringsFuncsWithOptions
+// but returns an error instead of panicking
+func (r Render) TryAddFromStringsFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	templateStrings ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newStringsBuilder(name, funcMap, options, templateStrings))
+}
+
+// TryAddFromFilesFuncs is like AddFromFilesFuncs but returns an error instead of panicking
+func (r Render) TryAddFromFilesFuncs(
+	name string,
+	funcMap template.FuncMap,
+	files ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newFilesBuilder(name, funcMap, *NewTemplateOptions(), files))
+}
+
+// TryAddFromFilesFuncsWithOptions is like AddFromFilesFuncsWithOptions
+// but returns an error instead of panicking
+func (r Render) TryAddFromFilesFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	files ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newFilesBuilder(name, funcMap, options, files))
+}
+
+// TryAddFromGlobFuncsWithOptions is like AddFromGlobFuncsWithOptions
+// but returns an error instead of panicking
+func (r Render) TryAddFromGlobFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	glob string,
+) (*template.Template, error) {
+	return r.addBuilder(newGlobBuilder(name, funcMap, options, glob))
+}
+
+// TryAddFromFSFuncsWithOptions is like AddFromFSFuncsWithOptions
+// but returns an error instead of panicking
+func (r Render) TryAddFromFSFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	fsys fs.FS,
+	files ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newFSBuilder(name, funcMap, options, fsys, files))
+}
+
+// addBuilder parses the template described by b and registers it
+func (r Render) addBuilder(b *templateBuilder) (*template.Template, error) {
+	tmpl, err := b.template()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.TryAdd(b.name, tmpl); err != nil {
+		return nil, err
+	}
+	setRenderInfo(tmpl, b.describe(tmpl))
+	return tmpl, nil
+}
+
+// mustTemplate panics if err is not nil, like template.Must
+func mustTemplate(tmpl *template.Template, err error) *template.Template {
+	if err != nil {
+		panic(err)
+	}
 	return tmpl
 }
 
-// Instance supply render string
+// Instance supply render string. A name of the form "page#block" renders only
+// the block of that template, as does a request marked partial by the
+// Partials middleware. The Localize middleware selects a locale variant of
+// the name. An unknown name renders a 500 error suggesting similar template
+// names.
 func (r Render) Instance(name string, data interface{}) render.Render {
-	return render.HTML{
-		Template: r[name],
-		Data:     data,
+	return newPartialRender(r, name, data)
+}
+
+// InstanceBlock renders only the template defined as block in the template
+// registered under name, e.g. a {{ define "row" }} fragment for htmx. An empty
+// block renders the whole template.
+func (r Render) InstanceBlock(name, block string, data any) render.Render {
+	tmpl, ok := r[name]
+	if !ok {
+		return internalError{err: notFoundError(name, r.Names())}
+	}
+	tmpl, err := lookupBlock(tmpl, name, block)
+	if err != nil {
+		return internalError{err: err}
 	}
+	return newHTML(tmpl, data)
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -18,12 +19,26 @@ const (
 )
 
 func performRequest(r http.Handler) *httptest.ResponseRecorder {
-	req, _ := http.NewRequestWithContext(context.Background(), "GET", "/", nil)
+	return performRequestPath(r, "/")
+}
+
+func performRequestPath(r http.Handler, path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", path, nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 	return w
 }
 
+// htmlRenderers returns a new renderer of each HTML type, the SyncRender
+// configured with opts
+func htmlRenderers(opts ...Option) map[string]Renderer {
+	return map[string]Renderer{
+		"Render":        New(),
+		"DynamicRender": NewDynamic(),
+		"SyncRender":    NewSync(opts...),
+	}
+}
+
 func createFromFile() Render {
 	r := New()
 	r.AddFromFiles("index", "tests/base.html", "tests/article.html")
@@ -173,3 +188,59 @@ func TestDuplicateTemplate(t *testing.T) {
 		r.AddFromString("index", "Welcome to {{ .name }} template")
 	})
 }
+
+func TestTryAddErrors(t *testing.T) {
+	r := New()
+	tmpl := template.Must(template.New("test").Parse("Welcome to {{ .name }} template"))
+
+	var te *TemplateError
+	err := r.TryAdd("", tmpl)
+	assert.ErrorIs(t, err, ErrEmptyName)
+
+	err = r.TryAdd("test", nil)
+	assert.ErrorIs(t, err, ErrNilTemplate)
+
+	assert.NoError(t, r.TryAdd("test", tmpl))
+	err = r.TryAdd("test", tmpl)
+	assert.ErrorIs(t, err, ErrTemplateExists)
+	assert.ErrorAs(t, err, &te)
+	assert.Equal(t, "test", te.Name)
+
+	_, err = r.TryAddFromFiles("none")
+	assert.ErrorIs(t, err, ErrNoFiles)
+
+	_, err = r.TryAddFromGlob("none", "tests/missing/*")
+	assert.ErrorIs(t, err, ErrNoFiles)
+
+	_, err = r.TryAddFromFiles("missing", "tests/missing.html")
+	assert.ErrorAs(t, err, &te)
+	assert.Equal(t, "tests/missing.html", te.File)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.NotContains(t, r, "missing")
+}
+
+func TestTryAddParseError(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "broken.html")
+	assert.NoError(t, os.WriteFile(file, []byte("<p>\n{{ .title }\n</p>"), 0o600))
+
+	r := New()
+	_, err := r.TryAddFromFiles("broken", "tests/base.html", file)
+	var te *TemplateError
+	assert.ErrorAs(t, err, &te)
+	assert.Equal(t, "broken", te.Name)
+	assert.Equal(t, file, te.File)
+	assert.Equal(t, 2, te.Line)
+	assert.Contains(t, te.Error(), file+":2: ")
+	assert.NotContains(t, r, "broken")
+
+	_, err = r.TryAddFromStringsFuncs("strings", template.FuncMap{}, "ok", "{{ missing }}")
+	assert.ErrorAs(t, err, &te)
+	assert.Equal(t, "strings", te.Name)
+	assert.Empty(t, te.File)
+	assert.Equal(t, 1, te.Line)
+
+	assert.PanicsWithError(t, err.Error(), func() {
+		r.AddFromStringsFuncs("strings", template.FuncMap{}, "ok", "{{ missing }}")
+	})
+}
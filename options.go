@@ -0,0 +1,51 @@
+package multitemplate
+
+import (
+	"html/template"
+	"maps"
+	"slices"
+)
+
+// Option configures a SyncRender, see NewSync, NewSyncDynamic and NewRenderer
+type Option func(*SyncRender)
+
+// WithFuncs makes the functions of funcMap available in every template of the
+// renderer, whichever loader registers it. Functions passed to an individual
+// Add*Funcs call take precedence over these. Templates registered with Add
+// are already parsed and are left untouched.
+func WithFuncs(funcMap template.FuncMap) Option {
+	return func(r *SyncRender) {
+		if r.funcMap == nil {
+			r.funcMap = make(template.FuncMap, len(funcMap))
+		}
+		maps.Copy(r.funcMap, funcMap)
+	}
+}
+
+// WithTemplateOptions applies html/template options such as "missingkey=error"
+// to every template of the renderer, whichever loader registers it. Options
+// given to an individual *WithOptions call are applied after these, so they
+// take precedence. Templates registered with Add are left untouched.
+func WithTemplateOptions(options ...string) Option {
+	return func(r *SyncRender) {
+		r.templateOptions = append(r.templateOptions, options...)
+	}
+}
+
+// mergeOptions returns the options of a template preceded by the renderer options
+func (r *SyncRender) mergeOptions(options TemplateOptions) TemplateOptions {
+	if len(r.templateOptions) > 0 {
+		options.Options = slices.Concat(r.templateOptions, options.Options)
+	}
+	return options
+}
+
+// mergeFuncs returns the renderer functions overridden by funcMap
+func (r *SyncRender) mergeFuncs(funcMap template.FuncMap) template.FuncMap {
+	if len(r.funcMap) == 0 {
+		return funcMap
+	}
+	merged := maps.Clone(r.funcMap)
+	maps.Copy(merged, funcMap)
+	return merged
+}
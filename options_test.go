@@ -0,0 +1,116 @@
+package multitemplate
+
+import (
+	"html/template"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFuncs(t *testing.T) {
+	r := NewSync(WithFuncs(template.FuncMap{"upper": strings.ToUpper}), WithFuncs(template.FuncMap{
+		"lower": strings.ToLower,
+	}))
+	r.AddFromFiles("files", "tests/funcs/upper.html")
+	r.AddFromGlob("glob", "tests/funcs/*.html")
+	r.AddFromFS("fs", os.DirFS("tests/funcs"), "upper.html")
+	r.AddFromString("string", `{{ upper .name }}`)
+	r.AddFromStringsFuncs("override", template.FuncMap{"upper": strings.ToLower}, `{{ upper .name }}`)
+	r.AddFromStringsFuncs("both", template.FuncMap{"title": strings.ToTitle}, `{{ lower .name }}{{ title .name }}`)
+
+	for name, expected := range map[string]string{
+		"files":    "GIN",
+		"glob":     "GIN",
+		"fs":       "GIN",
+		"string":   "GIN",
+		"override": "gin",
+		"both":     "ginGIN",
+	} {
+		s, err := r.RenderString(name, gin.H{"name": "Gin"})
+		assert.NoError(t, err, name)
+		assert.Equal(t, expected, s, name)
+	}
+
+	assert.NoError(t, r.Reload())
+	s, err := r.RenderString("files", gin.H{"name": "Gin"})
+	assert.NoError(t, err)
+	assert.Equal(t, "GIN", s)
+}
+
+func TestNewRendererWithOptions(t *testing.T) {
+	funcs := WithFuncs(template.FuncMap{"upper": strings.ToUpper})
+
+	r, ok := NewRenderer(funcs).(*SyncRender)
+	assert.True(t, ok)
+	assert.True(t, r.dynamic)
+
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(gin.DebugMode)
+	r, ok = NewRenderer(funcs).(*SyncRender)
+	assert.True(t, ok)
+	assert.False(t, r.dynamic)
+
+	_, ok = NewRenderer().(Render)
+	assert.True(t, ok)
+}
+
+func TestWithMissingKey(t *testing.T) {
+	strict := *NewTemplateOptions(WithMissingKey("error"))
+	data := map[string]any{}
+
+	r := New()
+	r.AddFromStringsFuncsWithOptions("default", nil, *NewTemplateOptions(), `{{ .name }}`)
+	r.AddFromStringsFuncsWithOptions("zero", nil, *NewTemplateOptions(WithOption("missingkey=zero")), `{{ .name }}`)
+	r.AddFromStringsFuncsWithOptions("strict", nil, strict, `{{ .name }}`)
+
+	s, err := r.RenderString("default", data)
+	assert.NoError(t, err)
+	assert.Empty(t, s)
+	_, err = r.RenderString("zero", data)
+	assert.NoError(t, err)
+	_, err = r.RenderString("strict", data)
+	assert.ErrorContains(t, err, `map has no entry for key "name"`)
+
+	info, ok := r.Describe("strict")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"missingkey=error"}, info.Options)
+
+	d := NewDynamic()
+	d.AddFromStringsFuncsWithOptions("strict", nil, strict, `{{ .name }}`)
+	_, err = d.RenderString("strict", data)
+	assert.ErrorContains(t, err, `map has no entry for key "name"`)
+}
+
+func TestInvalidTemplateOption(t *testing.T) {
+	options := *NewTemplateOptions(WithMissingKey("strict"))
+
+	_, err := New().TryAddFromStringsFuncsWithOptions("page", nil, options, `{{ .name }}`)
+	assert.ErrorIs(t, err, ErrInvalidOption)
+	_, err = NewText("text/plain").TryAddFromStringsFuncsWithOptions("page", nil, options, `{{ .name }}`)
+	assert.ErrorIs(t, err, ErrInvalidOption)
+	_, err = NewSync(WithTemplateOptions("unknown")).TryAddFromString("page", `{{ .name }}`)
+	assert.ErrorIs(t, err, ErrInvalidOption)
+}
+
+func TestWithTemplateOptions(t *testing.T) {
+	r := NewSync(WithTemplateOptions("missingkey=error"))
+	r.AddFromString("string", `{{ .name }}`)
+	r.AddFromStringsFuncsWithOptions("lenient", nil, *NewTemplateOptions(WithMissingKey("zero")), `{{ .name }}`)
+	r.Add("template", template.Must(template.New("template").Parse(`{{ .name }}`)))
+
+	_, err := r.RenderString("string", map[string]any{})
+	assert.ErrorContains(t, err, `map has no entry for key "name"`)
+	s, err := r.RenderString("lenient", map[string]any{})
+	assert.NoError(t, err)
+	assert.Empty(t, s)
+	s, err = r.RenderString("template", map[string]any{})
+	assert.NoError(t, err)
+	assert.Empty(t, s)
+
+	info, ok := r.Describe("lenient")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"missingkey=error", "missingkey=zero"}, info.Options)
+}
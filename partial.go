@@ -0,0 +1,134 @@
+package multitemplate
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+// partialKey is the key of the block selected by Partials in the gin.Context
+const partialKey = "multitemplate.partial"
+
+// defaultPartialHeaders are the request headers sent by htmx and Turbo for
+// requests that only need a fragment of the page
+var defaultPartialHeaders = []string{"HX-Request", "Turbo-Frame"}
+
+// PartialConfig configures the Partials middleware
+type PartialConfig struct {
+	// Block is the block rendered instead of the whole template for partial
+	// requests, e.g. "content".
+	Block string
+	// Headers are the request headers marking a partial request, HX-Request
+	// and Turbo-Frame when empty. Boosted htmx requests (HX-Boosted) always
+	// get the whole page.
+	Headers []string
+}
+
+// Partials returns a middleware that makes c.HTML render only block of the
+// requested template for htmx and Turbo requests, and the whole template
+// otherwise. Templates that do not define block are always rendered whole.
+func Partials(block string) gin.HandlerFunc {
+	return PartialsWithConfig(PartialConfig{Block: block})
+}
+
+// PartialsWithConfig is like Partials with a custom configuration
+func PartialsWithConfig(config PartialConfig) gin.HandlerFunc {
+	headers := config.Headers
+	if len(headers) == 0 {
+		headers = defaultPartialHeaders
+	}
+	return func(c *gin.Context) {
+		for _, h := range headers {
+			c.Writer.Header().Add("Vary", h)
+		}
+		if !isPartial(c.Request, headers) {
+			c.Next()
+			return
+		}
+
+		c.Set(partialKey, config.Block)
+		nextWithContext(c)
+	}
+}
+
+// isPartial reports whether the request carries one of headers and is not a
+// boosted htmx request
+func isPartial(req *http.Request, headers []string) bool {
+	if req.Header.Get("HX-Boosted") != "" {
+		return false
+	}
+	for _, h := range headers {
+		if req.Header.Get(h) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// partialBlock returns the block selected by the Partials middleware for w
+func partialBlock(w http.ResponseWriter) string {
+	if c, ok := requestContext(w); ok {
+		return c.GetString(partialKey)
+	}
+	return ""
+}
+
+// partialRender renders a template for a request: its locale variant selected
+// by the Localize middleware, then the block selected by the Partials
+// middleware when the template defines it, or the whole template
+type partialRender struct {
+	r     blockRenderer
+	name  string
+	block string // block requested by name, rendered regardless of Partials
+	data  any
+}
+
+// Render writes the block or the whole template to w
+func (p partialRender) Render(w http.ResponseWriter) error {
+	name := localize(p.r, p.name, w)
+	if p.block != "" {
+		return p.instance(name, p.block).Render(w)
+	}
+	if block := partialBlock(w); block != "" {
+		if _, err := p.r.lookup(name + blockSeparator + block); err == nil {
+			return p.instance(name, block).Render(w)
+		}
+	}
+	return p.instance(name, "").Render(w)
+}
+
+// WriteContentType writes the content type of the template
+func (p partialRender) WriteContentType(w http.ResponseWriter) {
+	p.instance(localize(p.r, p.name, w), p.block).WriteContentType(w)
+}
+
+// instance returns the render.Render of block in name, the variant of the
+// requested template
+func (p partialRender) instance(name, block string) render.Render {
+	if r, ok := p.r.(variantRenderer); ok {
+		return r.instanceVariant(p.name, name, block, p.data)
+	}
+	return p.r.InstanceBlock(name, block, p.data)
+}
+
+// blockRenderer is implemented by the HTML renderers of this package
+type blockRenderer interface {
+	Has(name string) bool
+	lookup(name string) (*template.Template, error)
+	InstanceBlock(name, block string, data any) render.Render
+}
+
+// variantRenderer is implemented by the renderers whose rendering depends on
+// the template requested by the handler, not only on the variant rendered
+type variantRenderer interface {
+	instanceVariant(requested, name, block string, data any) render.Render
+}
+
+// newPartialRender returns the render.Render of the template name, and of its
+// block when the name has the form "page#block", that adapts to the request
+func newPartialRender(r blockRenderer, name string, data any) render.Render {
+	name, block := splitBlock(name, r.Has)
+	return partialRender{r: r, name: name, block: block, data: data}
+}
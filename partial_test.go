@@ -0,0 +1,74 @@
+package multitemplate
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func performRequestHeader(r http.Handler, path string, header http.Header) *httptest.ResponseRecorder {
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, path, nil)
+	req.Header = header
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestPartials(t *testing.T) {
+	for name, r := range htmlRenderers() {
+		t.Run(name, func(t *testing.T) {
+			r.AddFromFiles("page", "tests/partials/layout.html", "tests/partials/page.html")
+			r.AddFromString("fragment", "<p>{{ . }}</p>")
+
+			router := gin.New()
+			router.HTMLRender = r
+			router.Use(Partials("content"))
+			router.GET("/:name", func(c *gin.Context) {
+				c.HTML(http.StatusOK, c.Param("name"), "hi")
+			})
+
+			w := performRequestHeader(router, "/page", http.Header{})
+			assert.Equal(t, "<html><body><main>hi</main></body></html>\n", w.Body.String())
+			assert.Equal(t, []string{"HX-Request", "Turbo-Frame"}, w.Header().Values("Vary"))
+
+			w = performRequestHeader(router, "/page", http.Header{"Hx-Request": {"true"}})
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "<main>hi</main>", w.Body.String())
+			assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+
+			w = performRequestHeader(router, "/page", http.Header{"Turbo-Frame": {"main"}})
+			assert.Equal(t, "<main>hi</main>", w.Body.String())
+
+			w = performRequestHeader(router, "/page", http.Header{"Hx-Request": {"true"}, "Hx-Boosted": {"true"}})
+			assert.Equal(t, "<html><body><main>hi</main></body></html>\n", w.Body.String())
+
+			w = performRequestHeader(router, "/fragment", http.Header{"Hx-Request": {"true"}})
+			assert.Equal(t, "<p>hi</p>", w.Body.String(), "templates without the block render whole")
+
+			w = performRequestHeader(router, "/missing", http.Header{"Hx-Request": {"true"}})
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+		})
+	}
+}
+
+func TestPartialsWithConfig(t *testing.T) {
+	r := NewSync(WithBufferedRendering())
+	r.AddFromFiles("page", "tests/partials/layout.html", "tests/partials/page.html")
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.Use(PartialsWithConfig(PartialConfig{Block: "content", Headers: []string{"X-Partial"}}))
+	router.GET("/", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "page", "hi")
+	})
+
+	w := performRequestHeader(router, "/", http.Header{"Hx-Request": {"true"}})
+	assert.Equal(t, "<html><body><main>hi</main></body></html>\n", w.Body.String())
+	w = performRequestHeader(router, "/", http.Header{"X-Partial": {"1"}})
+	assert.Equal(t, "<main>hi</main>", w.Body.String())
+	assert.Equal(t, "X-Partial", w.Header().Get("Vary"))
+}
@@ -0,0 +1,130 @@
+package multitemplate
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+// DataProvider returns values added to the data of the templates it is
+// registered for, such as the current user, a CSRF token or flash messages.
+// An error aborts the rendering with a 500 response.
+type DataProvider func(c *gin.Context) (gin.H, error)
+
+// dataProvider is a DataProvider with the template name pattern it applies
+// to, empty for every template
+type dataProvider struct {
+	pattern string
+	provide DataProvider
+}
+
+// WithDataProvider registers a provider whose values are merged into the data
+// of every template, see DataProviders for the precedence rules
+func WithDataProvider(provider DataProvider) Option {
+	return func(r *SyncRender) {
+		r.providers = append(r.providers, dataProvider{provide: provider})
+	}
+}
+
+// WithTemplateDataProvider registers a provider for the templates whose name
+// matches pattern, with the syntax of path.Match, e.g. "admin/*". It panics
+// if pattern is malformed.
+func WithTemplateDataProvider(pattern string, provider DataProvider) Option {
+	if _, err := path.Match(pattern, ""); err != nil {
+		panic(fmt.Errorf("multitemplate: data provider pattern %q: %w", pattern, err))
+	}
+	return func(r *SyncRender) {
+		r.providers = append(r.providers, dataProvider{pattern: pattern, provide: provider})
+	}
+}
+
+// DataProviders returns a middleware that gives the data providers of a
+// SyncRender access to the request, see WithDataProvider. Without it providers
+// are not called, which is logged as a warning in debug mode.
+//
+// When values collide, the data passed by the handler to c.HTML wins over
+// providers registered for the template, which win over providers registered
+// for every template. Among providers of the same kind the last registered
+// wins. Providers only apply to nil, gin.H and map[string]any data; other data
+// such as structs is passed to the template unchanged.
+func DataProviders() gin.HandlerFunc {
+	return nextWithContext
+}
+
+// providersFor returns the providers applying to the template name, those
+// for every template first
+func (r *SyncRender) providersFor(name string) []DataProvider {
+	var global, matched []DataProvider
+	for _, p := range r.providers {
+		switch ok, _ := path.Match(p.pattern, name); {
+		case p.pattern == "":
+			global = append(global, p.provide)
+		case ok:
+			matched = append(matched, p.provide)
+		}
+	}
+	return append(global, matched...)
+}
+
+// providedData renders a template once the values of the data providers are
+// merged into its data
+type providedData struct {
+	name      string
+	data      any
+	providers []DataProvider
+	render    func(data any) render.Render
+	onError   func(*TemplateError) render.Render
+}
+
+// Render calls the providers with the request context of w and renders the
+// template with the merged data
+func (p providedData) Render(w http.ResponseWriter) error {
+	c, ok := requestContext(w)
+	if !ok {
+		if gin.IsDebugging() {
+			fmt.Fprintf(gin.DefaultWriter, "[GIN-debug] [WARNING] multitemplate: data providers of %q "+
+				"are not called without the DataProviders middleware\n", p.name)
+		}
+		return p.render(p.data).Render(w)
+	}
+	data, err := p.merge(c)
+	if err != nil {
+		return p.onError(&TemplateError{Name: p.name, Err: fmt.Errorf("data provider: %w", err)}).Render(w)
+	}
+	return p.render(data).Render(w)
+}
+
+// WriteContentType writes the content type of the template
+func (p providedData) WriteContentType(w http.ResponseWriter) {
+	p.render(p.data).WriteContentType(w)
+}
+
+// merge returns the values of the providers overridden by the handler data,
+// or the handler data unchanged when it is not a map
+func (p providedData) merge(c *gin.Context) (any, error) {
+	var handler map[string]any
+	switch data := p.data.(type) {
+	case nil:
+	case gin.H:
+		handler = data
+	case map[string]any:
+		handler = data
+	default:
+		return p.data, nil
+	}
+
+	merged := gin.H{}
+	for _, provide := range p.providers {
+		values, err := provide(c)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(merged, values)
+	}
+	maps.Copy(merged, handler)
+	return merged, nil
+}
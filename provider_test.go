@@ -0,0 +1,101 @@
+package multitemplate
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataProviders(t *testing.T) {
+	r := NewSync(
+		WithDataProvider(func(c *gin.Context) (gin.H, error) {
+			return gin.H{"user": c.GetHeader("X-User"), "title": "Site", "section": "site"}, nil
+		}),
+		WithTemplateDataProvider("admin/*", func(*gin.Context) (gin.H, error) {
+			return gin.H{"section": "admin"}, nil
+		}),
+	)
+	r.AddFromString("index", `{{ .user }} {{ .title }} {{ .section }}`)
+	r.AddFromString("admin/index", `{{ .user }} {{ .title }} {{ .section }}`)
+	r.AddFromString("struct", `{{ .Title }}`)
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.Use(DataProviders())
+	router.GET("/", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "index", nil)
+	})
+	router.GET("/admin", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "admin/index", map[string]any{"title": "Admin"})
+	})
+	router.GET("/struct", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "struct", struct{ Title string }{"Post"})
+	})
+
+	w := performRequestHeader(router, "/", http.Header{"X-User": {"ada"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ada Site site", w.Body.String())
+
+	w = performRequestHeader(router, "/admin", http.Header{"X-User": {"ada"}})
+	assert.Equal(t, "ada Admin admin", w.Body.String(), "handler data wins, then template providers")
+
+	w = performRequestPath(router, "/struct")
+	assert.Equal(t, "Post", w.Body.String())
+}
+
+func TestDataProvidersWithoutMiddleware(t *testing.T) {
+	called := false
+	r := NewSync(WithDataProvider(func(*gin.Context) (gin.H, error) {
+		called = true
+		return gin.H{"title": "Site"}, nil
+	}))
+	r.AddFromString("index", `{{ .title }}`)
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.GET("/", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "index", gin.H{"title": "Home"})
+	})
+
+	var log bytes.Buffer
+	previous := gin.DefaultWriter
+	gin.DefaultWriter = &log
+	defer func() { gin.DefaultWriter = previous }()
+
+	w := performRequest(router)
+	assert.Equal(t, "Home", w.Body.String())
+	assert.False(t, called)
+	assert.Contains(t, log.String(), `data providers of "index" are not called without the DataProviders middleware`)
+}
+
+func TestDataProviderError(t *testing.T) {
+	r := NewSync(
+		WithDataProvider(func(*gin.Context) (gin.H, error) {
+			return nil, errors.New("session expired")
+		}),
+		WithErrorTemplate("error"),
+	)
+	r.AddFromString("index", `{{ .title }}`)
+	r.AddFromString("error", `failed: {{ .Err }}`)
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.Use(DataProviders(), Partials("content"))
+	router.GET("/", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "index", nil)
+	})
+
+	w := performRequestHeader(router, "/", http.Header{"Hx-Request": {"true"}})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "failed: data provider: session expired", w.Body.String())
+}
+
+func TestTemplateDataProviderBadPattern(t *testing.T) {
+	assert.Panics(t, func() {
+		WithTemplateDataProvider("[", func(*gin.Context) (gin.H, error) { return nil, nil })
+	})
+}
@@ -0,0 +1,86 @@
+package multitemplate
+
+import (
+	"errors"
+	"html/template"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Reloader is implemented by renderers that can rebuild all of their
+// templates at once, such as SyncRender.
+type Reloader interface {
+	Reload() error
+}
+
+var _ Reloader = (*SyncRender)(nil)
+
+// Reload parses every registered template again from its original sources.
+// The new set only replaces the current one if every template parsed; the
+// swap is atomic for concurrent renders. Otherwise the current templates are
+// left in place and the errors of all failed templates are returned together.
+func (r *SyncRender) Reload() error {
+	r.mu.RLock()
+	builders := make([]*templateBuilder, 0, len(r.builders))
+	for _, b := range r.builders {
+		builders = append(builders, b)
+	}
+	r.mu.RUnlock()
+
+	type rebuilt struct {
+		tmpl    *template.Template
+		sources []fileStamp
+	}
+	results := make([]rebuilt, len(builders))
+	var errs []error
+	for i, b := range builders {
+		tmpl, sources, err := b.parse()
+		if err != nil {
+			errs = append(errs, b.wrapError(err))
+			continue
+		}
+		results[i] = rebuilt{tmpl: tmpl, sources: sources}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, b := range builders {
+		b.swap(results[i].tmpl, results[i].sources)
+	}
+	return nil
+}
+
+// ReloadOnSignal reloads r every time the process receives one of sigs, or
+// SIGHUP when none are given. onReload, when not nil, is called with the
+// result of every reload. The returned function stops listening.
+func ReloadOnSignal(r Reloader, onReload func(error), sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				err := r.Reload()
+				if onReload != nil {
+					onReload(err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
@@ -0,0 +1,77 @@
+package multitemplate
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncRenderReload(t *testing.T) {
+	dir := t.TempDir()
+	index := filepath.Join(dir, "index.html")
+	article := filepath.Join(dir, "article.html")
+	assert.NoError(t, os.WriteFile(index, []byte("index v1"), 0o600))
+	assert.NoError(t, os.WriteFile(article, []byte("article v1"), 0o600))
+
+	r := NewSync()
+	r.AddFromFiles("index", index)
+	r.AddFromFiles("article", article)
+	r.AddFromString("string", "string")
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.GET("/", func(c *gin.Context) {
+		c.HTML(200, c.Query("name"), nil)
+	})
+	body := func(name string) string {
+		return performRequestPath(router, "/?name="+name).Body.String()
+	}
+
+	assert.NoError(t, os.WriteFile(index, []byte("index v2"), 0o600))
+	assert.NoError(t, os.WriteFile(article, []byte("article {{ v2"), 0o600))
+	err := r.Reload()
+	var te *TemplateError
+	assert.ErrorAs(t, err, &te)
+	assert.Equal(t, "article", te.Name)
+	assert.Equal(t, article, te.File)
+	assert.Equal(t, "index v1", body("index"), "nothing is swapped when one template fails")
+	assert.Equal(t, "article v1", body("article"))
+
+	assert.NoError(t, os.WriteFile(article, []byte("article v2"), 0o600))
+	assert.NoError(t, r.Reload())
+	assert.Equal(t, "index v2", body("index"))
+	assert.Equal(t, "article v2", body("article"))
+	assert.Equal(t, "string", body("string"))
+}
+
+func TestReloadOnSignal(t *testing.T) {
+	dir := t.TempDir()
+	index := filepath.Join(dir, "index.html")
+	assert.NoError(t, os.WriteFile(index, []byte("v1"), 0o600))
+
+	r := NewSync()
+	r.AddFromFiles("index", index)
+
+	reloaded := make(chan error, 1)
+	stop := ReloadOnSignal(r, func(err error) { reloaded <- err })
+	defer stop()
+
+	assert.NoError(t, os.WriteFile(index, []byte("v2"), 0o600))
+	p, err := os.FindProcess(os.Getpid())
+	assert.NoError(t, err)
+	assert.NoError(t, p.Signal(syscall.SIGHUP))
+
+	select {
+	case err := <-reloaded:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("template set was not reloaded")
+	}
+	tmpl, _, _ := r.template("index")
+	assert.Equal(t, "v2", tmpl.Tree.Root.String())
+}
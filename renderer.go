@@ -2,6 +2,7 @@ package multitemplate
 
 import (
 	"html/template"
+	"io"
 	"io/fs"
 
 	"github.com/gin-gonic/gin/render"
@@ -11,6 +12,18 @@ import (
 // When gin is in debug mode then all multitemplates works with
 // hot reloading allowing you modify file templates and seeing changes instantly.
 // Renderer should be created using multitemplate.NewRenderer() constructor.
+//
+// Every Add* method panics when the template cannot be parsed or registered.
+// The matching TryAdd* method returns a *TemplateError instead, so callers
+// can collect all failures and report them together.
+//
+// Execute and RenderString render a registered template without a gin
+// context, e.g. for emails or background jobs.
+//
+// The middlewares of this package, such as Partials and DataProviders, pass
+// the request to the renderers through c.Writer. Middlewares registered after
+// them that replace c.Writer must wrap it with an Unwrap() http.ResponseWriter
+// method.
 type Renderer interface {
 	render.HTMLRender
 	Add(name string, tmpl *template.Template)
@@ -33,4 +46,63 @@ type Renderer interface {
 		options TemplateOptions,
 		files ...string,
 	) *template.Template
+	AddFromGlobFuncsWithOptions(
+		name string,
+		funcMap template.FuncMap,
+		options TemplateOptions,
+		glob string,
+	) *template.Template
+	AddFromFSFuncsWithOptions(
+		name string,
+		funcMap template.FuncMap,
+		options TemplateOptions,
+		fsys fs.FS,
+		files ...string,
+	) *template.Template
+
+	TryAdd(name string, tmpl *template.Template) error
+	TryAddFromFiles(name string, files ...string) (*template.Template, error)
+	TryAddFromGlob(name, glob string) (*template.Template, error)
+	TryAddFromFS(name string, fsys fs.FS, files ...string) (*template.Template, error)
+	TryAddFromFSFuncs(name string, funcMap template.FuncMap, fsys fs.FS, files ...string) (*template.Template, error)
+	TryAddFromString(name, templateString string) (*template.Template, error)
+	TryAddFromStringsFuncs(name string, funcMap template.FuncMap, templateStrings ...string) (*template.Template, error)
+	TryAddFromStringsFuncsWithOptions(
+		name string,
+		funcMap template.FuncMap,
+		options TemplateOptions,
+		templateStrings ...string,
+	) (*template.Template, error)
+	TryAddFromFilesFuncs(name string, funcMap template.FuncMap, files ...string) (*template.Template, error)
+	TryAddFromFilesFuncsWithOptions(
+		name string,
+		funcMap template.FuncMap,
+		options TemplateOptions,
+		files ...string,
+	) (*template.Template, error)
+	TryAddFromGlobFuncsWithOptions(
+		name string,
+		funcMap template.FuncMap,
+		options TemplateOptions,
+		glob string,
+	) (*template.Template, error)
+	TryAddFromFSFuncsWithOptions(
+		name string,
+		funcMap template.FuncMap,
+		options TemplateOptions,
+		fsys fs.FS,
+		files ...string,
+	) (*template.Template, error)
+
+	LoadDirectory(root string, configs ...LoadConfig) error
+	LoadFS(fsys fs.FS, configs ...LoadConfig) error
+
+	InstanceBlock(name, block string, data any) render.Render
+
+	Names() []string
+	Has(name string) bool
+	Describe(name string) (TemplateInfo, bool)
+
+	Execute(w io.Writer, name string, data any) error
+	RenderString(name string, data any) (string, error)
 }
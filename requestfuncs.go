@@ -0,0 +1,198 @@
+package multitemplate
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"net/http"
+	"runtime"
+	"sync"
+	"weak"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+// requestFuncsKey is the key of the requestFuncs bound by BindRequestFuncs in
+// the gin.Context
+const requestFuncsKey = "multitemplate.funcs"
+
+// RequestFunc returns the implementation of a template function for the
+// request of c, e.g.
+//
+//	func(c *gin.Context) any {
+//		return func() string { return c.Request.URL.Path }
+//	}
+type RequestFunc func(c *gin.Context) any
+
+// RequestFuncMap maps the names of template functions to their RequestFunc
+type RequestFuncMap map[string]RequestFunc
+
+// unboundFunc stands for a request function while a template is parsed, and
+// when it is executed outside of BindRequestFuncs
+type unboundFunc func(args ...any) (any, error)
+
+// FuncMap returns the functions to parse templates with, merged into the
+// funcMap given to a loader. Templates using them are executed with the
+// functions of the request when rendered under BindRequestFuncs, and fail
+// with ErrUnboundFunc otherwise.
+func (m RequestFuncMap) FuncMap() template.FuncMap {
+	funcMap := make(template.FuncMap, len(m))
+	for name := range m {
+		funcMap[name] = unboundFunc(func(...any) (any, error) {
+			return nil, fmt.Errorf("%w: %s", ErrUnboundFunc, name)
+		})
+	}
+	return funcMap
+}
+
+// unboundFuncs returns the functions of funcMap standing for request functions
+func unboundFuncs(funcMap template.FuncMap) template.FuncMap {
+	unbound := make(template.FuncMap)
+	for name, fn := range funcMap {
+		if _, ok := fn.(unboundFunc); ok {
+			unbound[name] = fn
+		}
+	}
+	return unbound
+}
+
+// BindRequestFuncs returns a middleware that binds the functions of funcs to
+// the request for the templates rendered by c.HTML. Templates using them are
+// executed on a clone taken from a pool, so that concurrent requests do not
+// share functions. Nested middlewares add their functions to the ones of the
+// enclosing ones.
+func BindRequestFuncs(funcs RequestFuncMap) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bound := funcs
+		if outer, ok := boundRequestFuncs(c); ok {
+			bound = mergeRequestFuncs(outer.funcs, funcs)
+		}
+		c.Set(requestFuncsKey, &requestFuncs{funcs: bound})
+		nextWithContext(c)
+	}
+}
+
+// mergeRequestFuncs returns the functions of outer overridden by inner
+func mergeRequestFuncs(outer, inner RequestFuncMap) RequestFuncMap {
+	merged := maps.Clone(outer)
+	maps.Copy(merged, inner)
+	return merged
+}
+
+// requestFuncs are the request functions bound to a request
+type requestFuncs struct {
+	funcs RequestFuncMap
+	bound template.FuncMap // built by the first render of the request
+}
+
+// boundRequestFuncs returns the request functions bound to c by BindRequestFuncs
+func boundRequestFuncs(c *gin.Context) (*requestFuncs, bool) {
+	v, _ := c.Get(requestFuncsKey)
+	funcs, ok := v.(*requestFuncs)
+	return funcs, ok
+}
+
+// funcMap returns the functions bound to the request of c
+func (f *requestFuncs) funcMap(c *gin.Context) template.FuncMap {
+	if f.bound == nil {
+		f.bound = make(template.FuncMap, len(f.funcs))
+		for name, fn := range f.funcs {
+			f.bound[name] = fn(c)
+		}
+	}
+	return f.bound
+}
+
+// boundSet is a template of a set using request functions
+type boundSet struct {
+	name    string
+	clones  *sync.Pool       // clones of the set made from a copy never executed
+	unbound template.FuncMap // placeholders of the request functions of the set
+}
+
+// boundTemplates maps the templates using request functions to their
+// boundSet. html/template cannot clone a template once it has executed, so
+// the copy the clones are made from is taken when the set is built.
+var boundTemplates sync.Map // weak.Pointer[template.Template] -> boundSet
+
+// bindable prepares the clones used to bind the request functions of
+// funcMap to tmpl, which must not have been executed yet
+func bindable(tmpl *template.Template, funcMap template.FuncMap) *template.Template {
+	unbound := unboundFuncs(funcMap)
+	if len(unbound) == 0 {
+		return tmpl
+	}
+	master, err := tmpl.Clone()
+	if err != nil {
+		return tmpl
+	}
+	clones := &sync.Pool{New: func() any {
+		clone, err := master.Clone()
+		if err != nil {
+			return nil
+		}
+		return clone
+	}}
+	for _, t := range tmpl.Templates() {
+		key := weak.Make(t)
+		boundTemplates.Store(key, boundSet{name: t.Name(), clones: clones, unbound: unbound})
+		runtime.AddCleanup(t, func(key weak.Pointer[template.Template]) {
+			boundTemplates.Delete(key)
+		}, key)
+	}
+	return tmpl
+}
+
+// newHTML returns the render.Render executing tmpl, which binds the request
+// functions when tmpl uses some
+func newHTML(tmpl *template.Template, data any) render.Render {
+	if _, ok := boundTemplates.Load(weak.Make(tmpl)); ok {
+		return boundHTML{Template: tmpl, Data: data}
+	}
+	return render.HTML{Template: tmpl, Data: data}
+}
+
+// boundHTML is render.HTML for a template using request functions
+type boundHTML struct {
+	Template *template.Template
+	Data     any
+}
+
+// Render executes the template with the request functions of w
+func (r boundHTML) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return executeBound(w, w, r.Template, r.Data)
+}
+
+// WriteContentType writes the HTML content type
+func (r boundHTML) WriteContentType(w http.ResponseWriter) {
+	bufferedHTML{}.WriteContentType(w)
+}
+
+// executeBound executes tmpl into out, on a clone with the request
+// functions bound when w comes from BindRequestFuncs and tmpl uses some. The
+// functions a previous request bound to the clone are reset first, so that
+// those the request does not bind fail instead of using its context.
+func executeBound(out io.Writer, w http.ResponseWriter, tmpl *template.Template, data any) error {
+	c, ok := requestContext(w)
+	if !ok {
+		return tmpl.Execute(out, data)
+	}
+	funcs, ok := boundRequestFuncs(c)
+	if !ok {
+		return tmpl.Execute(out, data)
+	}
+	v, ok := boundTemplates.Load(weak.Make(tmpl))
+	if !ok {
+		return tmpl.Execute(out, data)
+	}
+	set := v.(boundSet)
+	clone, ok := set.clones.Get().(*template.Template)
+	if !ok {
+		return tmpl.Execute(out, data)
+	}
+	defer set.clones.Put(clone)
+	return clone.Funcs(set.unbound).Funcs(funcs.funcMap(c)).ExecuteTemplate(out, set.name, data)
+}
@@ -0,0 +1,166 @@
+package multitemplate
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRequestFuncs = RequestFuncMap{
+	"currentPath": func(c *gin.Context) any {
+		return func() string { return c.Request.URL.Path }
+	},
+	"query": func(c *gin.Context) any {
+		return c.Query
+	},
+}
+
+func TestBindRequestFuncs(t *testing.T) {
+	funcMap := testRequestFuncs.FuncMap()
+	funcMap["upper"] = strings.ToUpper
+	for name, r := range htmlRenderers(WithBufferedRendering()) {
+		t.Run(name, func(t *testing.T) {
+			r.AddFromStringsFuncs("page", funcMap,
+				`{{ define "nav" }}<a>{{ currentPath }}</a>{{ end }}{{ template "nav" }} {{ upper (query "q") }}`)
+			r.AddFromString("static", `{{ . }}`)
+
+			router := gin.New()
+			router.HTMLRender = r
+			router.Use(BindRequestFuncs(testRequestFuncs))
+			router.GET("/*path", func(c *gin.Context) {
+				c.HTML(http.StatusOK, c.Query("name"), "hi")
+			})
+
+			var wg sync.WaitGroup
+			for i := range 20 {
+				wg.Go(func() {
+					w := performRequestPath(router, fmt.Sprintf("/p%d?name=page&q=v%d", i, i))
+					assert.Equal(t, fmt.Sprintf("<a>/p%d</a> V%d", i, i), w.Body.String())
+				})
+			}
+			wg.Wait()
+
+			w := performRequestPath(router, "/p?name=page%23nav")
+			assert.Equal(t, "<a>/p</a>", w.Body.String())
+			w = performRequestPath(router, "/?name=static")
+			assert.Equal(t, "hi", w.Body.String())
+		})
+	}
+}
+
+func TestNestedBindRequestFuncs(t *testing.T) {
+	r := New()
+	r.AddFromStringsFuncs("page", testRequestFuncs.FuncMap(), `{{ currentPath }} {{ query "q" }}`)
+
+	admin := BindRequestFuncs(RequestFuncMap{
+		"currentPath": func(*gin.Context) any {
+			return func() string { return "admin" }
+		},
+	})
+	router := gin.New()
+	router.HTMLRender = r
+	router.GET("/admin/", BindRequestFuncs(testRequestFuncs), admin, func(c *gin.Context) {
+		c.HTML(http.StatusOK, "page", nil)
+	})
+	router.GET("/shop/", BindRequestFuncs(RequestFuncMap{
+		"query": func(*gin.Context) any {
+			return func(string) string { return "shop" }
+		},
+	}), admin, func(c *gin.Context) {
+		c.HTML(http.StatusOK, "page", nil)
+	})
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			w := performRequestPath(router, fmt.Sprintf("/admin/?q=%d", i))
+			assert.Equal(t, fmt.Sprintf("admin %d", i), w.Body.String())
+		})
+		wg.Go(func() {
+			w := performRequestPath(router, "/shop/")
+			assert.Equal(t, "admin shop", w.Body.String())
+		})
+	}
+	wg.Wait()
+}
+
+func TestUnboundRequestFuncs(t *testing.T) {
+	r := New()
+	r.AddFromStringsFuncs("page", testRequestFuncs.FuncMap(), `{{ currentPath }}`)
+
+	err := r.Execute(io.Discard, "page", nil)
+	assert.ErrorIs(t, err, ErrUnboundFunc)
+	assert.ErrorContains(t, err, "currentPath")
+}
+
+func TestRequestFuncsNotShared(t *testing.T) {
+	funcs := RequestFuncMap{
+		"currentPath": testRequestFuncs["currentPath"],
+		"user": func(c *gin.Context) any {
+			user := c.Query("user")
+			return func() string { return user }
+		},
+	}
+	r := New()
+	r.AddFromStringsFuncs("page", funcs.FuncMap(), `{{ currentPath }} {{ user }}`)
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.GET("/both", BindRequestFuncs(funcs), func(c *gin.Context) {
+		c.HTML(http.StatusOK, "page", nil)
+	})
+	router.GET("/path", BindRequestFuncs(RequestFuncMap{"currentPath": funcs["currentPath"]}), func(c *gin.Context) {
+		c.HTML(http.StatusOK, "page", nil)
+	})
+
+	for range 5 {
+		w := performRequestPath(router, "/both?user=ada")
+		assert.Equal(t, "/both ada", w.Body.String())
+		w = performRequestPath(router, "/path")
+		assert.NotContains(t, w.Body.String(), "ada")
+	}
+}
+
+// BenchmarkRequestFuncs compares the rendering of a page using a request
+// function with the same page using a regular function
+func BenchmarkRequestFuncs(b *testing.B) {
+	const page = `{{ define "nav" }}<nav>{{ range .Links }}<a href="{{ . }}">{{ . }}</a>{{ end }}</nav>{{ end }}` +
+		`<html><body>{{ template "nav" . }}<p>{{ path }}</p></body></html>`
+	data := gin.H{"Links": []string{"/", "/about", "/blog", "/contact"}}
+	funcs := RequestFuncMap{
+		"path": func(c *gin.Context) any {
+			return func() string { return c.Request.URL.Path }
+		},
+	}
+
+	static := New()
+	static.AddFromStringsFuncs("page", template.FuncMap{"path": func() string { return "/" }}, page)
+	bound := New()
+	bound.AddFromStringsFuncs("page", funcs.FuncMap(), page)
+
+	for _, bc := range []struct {
+		name string
+		r    Render
+	}{{"static", static}, {"bound", bound}} {
+		router := gin.New()
+		router.HTMLRender = bc.r
+		router.Use(BindRequestFuncs(funcs))
+		router.GET("/", func(c *gin.Context) {
+			c.HTML(http.StatusOK, "page", data)
+		})
+
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				performRequest(router)
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package multitemplate
+
+import (
+	"html/template"
+	"io/fs"
+	"sync"
+
+	"github.com/gin-gonic/gin/render"
+)
+
+// SyncRender is a Renderer that is safe for concurrent use, so templates can
+// be added, replaced and removed while requests are being served. Adding a
+// template under an existing name replaces it once the new one has parsed.
+//
+// A SyncRender created with NewSync parses templates once, like Render; one
+// created with NewSyncDynamic rebuilds them when their files change, like
+// DynamicRender.
+type SyncRender struct {
+	mu       sync.RWMutex
+	builders map[string]*templateBuilder
+	dynamic  bool
+	funcMap  template.FuncMap
+	missing  MissingTemplateFunc
+
+	templateOptions []string
+	providers       []dataProvider
+
+	buffered      bool
+	errorTemplate string
+}
+
+var (
+	_ render.HTMLRender = (*SyncRender)(nil)
+	_ Renderer          = (*SyncRender)(nil)
+)
+
+// NewSync creates a SyncRender that parses every template once
+func NewSync(opts ...Option) *SyncRender {
+	return newSync(false, opts)
+}
+
+// NewSyncDynamic creates a SyncRender that rebuilds templates when their files change
+func NewSyncDynamic(opts ...Option) *SyncRender {
+	return newSync(true, opts)
+}
+
+func newSync(dynamic bool, opts []Option) *SyncRender {
+	r := &SyncRender{
+		builders: make(map[string]*templateBuilder),
+		dynamic:  dynamic,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Add new template
+func (r *SyncRender) Add(name string, tmpl *template.Template) {
+	if err := r.TryAdd(name, tmpl); err != nil {
+		panic(err)
+	}
+}
+
+// AddFromFiles supply add template from files
+func (r *SyncRender) AddFromFiles(name string, files ...string) *template.Template {
+	return mustTemplate(r.TryAddFromFiles(name, files...))
+}
+
+// AddFromGlob supply add template from global path
+func (r *SyncRender) AddFromGlob(name, glob string) *template.Template {
+	return mustTemplate(r.TryAddFromGlob(name, glob))
+}
+
+// AddFromFS supply add template from fs.FS (e.g. embed.FS)
+func (r *SyncRender) AddFromFS(name string, fsys fs.FS, files ...string) *template.Template {
+	return mustTemplate(r.TryAddFromFS(name, fsys, files...))
+}
+
+// AddFromFSFuncs supply add template from fs.FS (e.g. embed.FS) with callback func
+func (r *SyncRender) AddFromFSFuncs(
+	name string,
+	funcMap template.FuncMap,
+	fsys fs.FS,
+	files ...string,
+) *template.Template {
+	return mustTemplate(r.TryAddFromFSFuncs(name, funcMap, fsys, files...))
+}
+
+// AddFromString supply add template from strings
+func (r *SyncRender) AddFromString(name, templateString string) *template.Template {
+	return mustTemplate(r.TryAddFromString(name, templateString))
+}
+
+// AddFromStringsFuncs supply add template from strings
+func (r *SyncRender) AddFromStringsFuncs(
+	name string,
+	funcMap template.FuncMap,
+	templateStrings ...string,
+) *template.Template {
+	return mustTemplate(r.TryAddFromStringsFuncs(name, funcMap, templateStrings...))
+}
+
+// AddFromStringsFuncsWithOptions supply add template from strings with options
+func (r *SyncRender) AddFromStringsFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	templateStrings ...string,
+) *template.Template {
+	return mustTemplate(r.TryAddFromStringsFuncsWithOptions(name, funcMap, options, templateStrings...))
+}
+
+// AddFromFilesFuncs supply add template from file callback func
+func (r *SyncRender) AddFromFilesFuncs(name string, funcMap template.FuncMap, files ...string) *template.Template {
+	return mustTemplate(r.TryAddFromFilesFuncs(name, funcMap, files...))
+}
+
+// AddFromFilesFuncsWithOptions supply add template from file callback func with options
+func (r *SyncRender) AddFromFilesFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	files ...string,
+) *template.Template {
+	return mustTemplate(r.TryAddFromFilesFuncsWithOptions(name, funcMap, options, files...))
+}
+
+// AddFromGlobFuncsWithOptions supply add template from global path with callback func and options
+func (r *SyncRender) AddFromGlobFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	glob string,
+) *template.Template {
+	return mustTemplate(r.TryAddFromGlobFuncsWithOptions(name, funcMap, options, glob))
+}
+
+// AddFromFSFuncsWithOptions supply add template from fs.FS (e.g. embed.FS) with callback func and options
+func (r *SyncRender) AddFromFSFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	fsys fs.FS,
+	files ...string,
+) *template.Template {
+	return mustTemplate(r.TryAddFromFSFuncsWithOptions(name, funcMap, options, fsys, files...))
+}
+
+// TryAdd is like Add but returns an error instead of panicking
+func (r *SyncRender) TryAdd(name string, tmpl *template.Template) error {
+	if tmpl == nil {
+		return &TemplateError{Name: name, Err: ErrNilTemplate}
+	}
+	_, err := r.addBuilder(newTemplateBuilder(name, tmpl))
+	return err
+}
+
+// TryAddFromFiles is like AddFromFiles but returns an error instead of panicking
+func (r *SyncRender) TryAddFromFiles(name string, files ...string) (*template.Template, error) {
+	return r.addBuilder(newFilesBuilder(name, nil, *NewTemplateOptions(), files))
+}
+
+// TryAddFromGlob is like AddFromGlob but returns an error instead of panicking
+func (r *SyncRender) TryAddFromGlob(name, glob string) (*template.Template, error) {
+	return r.addBuilder(newGlobBuilder(name, nil, *NewTemplateOptions(), glob))
+}
+
+// TryAddFromFS is like AddFromFS but returns an error instead of panicking
+func (r *SyncRender) TryAddFromFS(name string, fsys fs.FS, files ...string) (*template.Template, error) {
+	return r.addBuilder(newFSBuilder(name, nil, *NewTemplateOptions(), fsys, files))
+}
+
+// TryAddFromFSFuncs is like AddFromFSFuncs but returns an error instead of panicking
+func (r *SyncRender) TryAddFromFSFuncs(
+	name string,
+	funcMap template.FuncMap,
+	fsys fs.FS,
+	files ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newFSBuilder(name, funcMap, *NewTemplateOptions(), fsys, files))
+}
+
+// TryAddFromString is like AddFromString but returns an error instead of panicking
+func (r *SyncRender) TryAddFromString(name, templateString string) (*template.Template, error) {
+	return r.addBuilder(newStringsBuilder(name, nil, *NewTemplateOptions(), []string{templateString}))
+}
+
+// TryAddFromStringsFuncs is like AddFromStringsFuncs but returns an error instead of panicking
+func (r *SyncRender) TryAddFromStringsFuncs(
+	name string,
+	funcMap template.FuncMap,
+	templateStrings ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newStringsBuilder(name, funcMap, *NewTemplateOptions(), templateStrings))
+}
+
+// TryAddFromStringsFuncsWithOptions is like AddFromStringsFuncsWithOptions
+// but returns an error instead of panicking
+func (r *SyncRender) TryAddFromStringsFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	templateStrings ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newStringsBuilder(name, funcMap, options, templateStrings))
+}
+
+// TryAddFromFilesFuncs is like AddFromFilesFuncs but returns an error instead of panicking
+func (r *SyncRender) TryAddFromFilesFuncs(
+	name string,
+	funcMap template.FuncMap,
+	files ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newFilesBuilder(name, funcMap, *NewTemplateOptions(), files))
+}
+
+// TryAddFromFilesFuncsWithOptions is like AddFromFilesFuncsWithOptions
+// but returns an error instead of panicking
+func (r *SyncRender) TryAddFromFilesFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	files ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newFilesBuilder(name, funcMap, options, files))
+}
+
+// TryAddFromGlobFuncsWithOptions is like AddFromGlobFuncsWithOptions
+// but returns an error instead of panicking
+func (r *SyncRender) TryAddFromGlobFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	glob string,
+) (*template.Template, error) {
+	return r.addBuilder(newGlobBuilder(name, funcMap, options, glob))
+}
+
+// TryAddFromFSFuncsWithOptions is like AddFromFSFuncsWithOptions
+// but returns an error instead of panicking
+func (r *SyncRender) TryAddFromFSFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	fsys fs.FS,
+	files ...string,
+) (*template.Template, error) {
+	return r.addBuilder(newFSBuilder(name, funcMap, options, fsys, files))
+}
+
+// LoadDirectory registers a template for every page under root, composed with
+// the layouts and partials of its LoadConfig. Pass several configs to use
+// different layouts for different groups of pages. All parse errors are
+// returned together.
+func (r *SyncRender) LoadDirectory(root string, configs ...LoadConfig) error {
+	return loadDirectory(root, configs, r.TryAddFromFilesFuncsWithOptions)
+}
+
+// LoadFS is like LoadDirectory but reads the templates from fsys (e.g. embed.FS)
+func (r *SyncRender) LoadFS(fsys fs.FS, configs ...LoadConfig) error {
+	return loadFS(fsys, configs, r.TryAddFromFSFuncsWithOptions)
+}
+
+// Remove unregisters the template with the given name and reports whether it existed
+func (r *SyncRender) Remove(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.builders[name]
+	delete(r.builders, name)
+	return ok
+}
+
+// addBuilder builds the template described by b outside the lock and
+// registers it, replacing any template with the same name
+func (r *SyncRender) addBuilder(b *templateBuilder) (*template.Template, error) {
+	if len(b.name) == 0 {
+		return nil, &TemplateError{Err: ErrEmptyName}
+	}
+	if b.buildType != templateType {
+		b.funcMap = r.mergeFuncs(b.funcMap)
+		b.options = r.mergeOptions(b.options)
+	}
+	tmpl, err := b.template()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.builders[b.name] = b
+	return tmpl, nil
+}
+
+// template returns the current template registered under name, rebuilding
+// it first in dynamic mode. The read lock is held so that a concurrent
+// Reload is seen either entirely or not at all.
+func (r *SyncRender) template(name string) (*template.Template, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	b, ok := r.builders[name]
+	switch {
+	case !ok:
+		return nil, false, nil
+	case r.dynamic:
+		tmpl, err := b.template()
+		return tmpl, true, err
+	default:
+		return b.current(), true, nil
+	}
+}
+
+// Instance supply render string. A name of the form "page#block" renders only
+// the block of that template, as does a request marked partial by the
+// Partials middleware. The Localize middleware selects a locale variant of
+// the name. An unknown name renders a 500 error suggesting similar template
+// names, unless WithMissingTemplate or WithFallbackTemplate
+// configured another policy. The values of the data providers are merged into
+// data, see DataProviders.
+func (r *SyncRender) Instance(name string, data any) render.Render {
+	return newPartialRender(r, name, data)
+}
+
+// InstanceBlock renders only the template defined as block in the template
+// registered under name, e.g. a {{ define "row" }} fragment for htmx. An empty
+// block renders the whole template.
+func (r *SyncRender) InstanceBlock(name, block string, data any) render.Render {
+	return r.instanceVariant(name, name, block, data)
+}
+
+// instanceVariant is InstanceBlock for name, the variant of the template
+// requested by the handler, e.g. its locale variant. Data providers are
+// selected by the requested name.
+func (r *SyncRender) instanceVariant(requested, name, block string, data any) render.Render {
+	tmpl, ok, err := r.template(name)
+	if !ok {
+		err := notFoundError(name, r.Names())
+		if r.missing != nil {
+			return r.missing(name, data, err)
+		}
+		return internalError{err: err}
+	}
+	if err != nil {
+		return errorPage{err: err}
+	}
+	tmpl, err = lookupBlock(tmpl, name, block)
+	if err != nil {
+		return internalError{err: err}
+	}
+	if providers := r.providersFor(requested); len(providers) > 0 {
+		return providedData{
+			name:      name,
+			data:      data,
+			providers: providers,
+			render:    func(data any) render.Render { return r.execute(tmpl, name, data) },
+			onError:   r.executionError,
+		}
+	}
+	return r.execute(tmpl, name, data)
+}
+
+// execute returns the render.Render executing tmpl with data
+func (r *SyncRender) execute(tmpl *template.Template, name string, data any) render.Render {
+	if r.buffered {
+		return bufferedHTML{
+			Template: tmpl,
+			Name:     name,
+			Data:     data,
+			onError:  r.executionError,
+		}
+	}
+	return newHTML(tmpl, data)
+}
@@ -0,0 +1,126 @@
+package multitemplate
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncRenderLoaders(t *testing.T) {
+	for _, r := range []*SyncRender{NewSync(), NewSyncDynamic()} {
+		r.AddFromFiles("files", "tests/base.html", "tests/article.html")
+		r.AddFromGlob("glob", "tests/global/*")
+		r.AddFromFS("fs", os.DirFS("."), "tests/base.html", "tests/article.html")
+		r.AddFromFSFuncs("fsfuncs", template.FuncMap{}, os.DirFS("."), "tests/base.html", "tests/article.html")
+		r.AddFromString("string", "Welcome to {{ .name }} template")
+		r.AddFromStringsFuncs("strings", template.FuncMap{},
+			`Welcome to {{ .name }} {{template "content"}}`, `{{define "content"}}template{{end}}`)
+		r.AddFromFilesFuncs("filesfuncs", template.FuncMap{}, "tests/welcome.html", "tests/content.html")
+		r.Add("tmpl", template.Must(template.New("tmpl").Parse("Welcome to {{ .name }} template")))
+
+		router := gin.New()
+		router.HTMLRender = r
+		router.GET("/", func(c *gin.Context) {
+			c.HTML(200, c.Query("name"), gin.H{"title": testTemplateTitle, "name": testTemplateName})
+		})
+
+		for name, body := range map[string]string{
+			"files":      "<p>Test Multiple Template</p>\nHi, this is article template\n",
+			"glob":       "<p>Test Multiple Template</p>\nHi, this is login template\n",
+			"fs":         "<p>Test Multiple Template</p>\nHi, this is article template\n",
+			"fsfuncs":    "<p>Test Multiple Template</p>\nHi, this is article template\n",
+			"string":     "Welcome to index template",
+			"strings":    "Welcome to index template",
+			"filesfuncs": "Welcome to index template\n",
+			"tmpl":       "Welcome to index template",
+		} {
+			w := performRequestPath(router, "/?name="+name)
+			assert.Equal(t, 200, w.Code, name)
+			assert.Equal(t, body, w.Body.String(), name)
+		}
+	}
+}
+
+func TestSyncRenderReplaceAndRemove(t *testing.T) {
+	r := NewSync()
+	r.AddFromString("index", "first")
+	r.AddFromString("index", "second")
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.GET("/", func(c *gin.Context) {
+		c.HTML(200, "index", nil)
+	})
+	assert.Equal(t, "second", performRequest(router).Body.String())
+
+	_, err := r.TryAddFromString("index", "{{ broken")
+	assert.Error(t, err)
+	assert.Equal(t, "second", performRequest(router).Body.String(), "a failed replace keeps the old template")
+
+	assert.True(t, r.Remove("index"))
+	assert.False(t, r.Remove("index"))
+	assert.Equal(t, http.StatusInternalServerError, performRequest(router).Code)
+
+	assert.ErrorIs(t, r.TryAdd("", template.New("x")), ErrEmptyName)
+	assert.ErrorIs(t, r.TryAdd("x", nil), ErrNilTemplate)
+}
+
+func TestSyncRenderStaticDoesNotReload(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "index.html")
+	assert.NoError(t, os.WriteFile(file, []byte("first"), 0o600))
+
+	static, dynamic := NewSync(), NewSyncDynamic()
+	static.AddFromFiles("index", file)
+	dynamic.AddFromFiles("index", file)
+	assert.NoError(t, os.WriteFile(file, []byte("second!"), 0o600))
+
+	tmpl, ok, err := static.template("index")
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.NotNil(t, tmpl.Lookup("index.html"))
+	assert.Equal(t, "first", tmpl.Tree.Root.String())
+
+	tmpl, _, err = dynamic.template("index")
+	assert.NoError(t, err)
+	assert.Equal(t, "second!", tmpl.Tree.Root.String())
+}
+
+func TestSyncRenderConcurrentUse(t *testing.T) {
+	r := NewSyncDynamic()
+	r.AddFromString("index", "index")
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.GET("/", func(c *gin.Context) {
+		c.HTML(200, "index", nil)
+	})
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				assert.Equal(t, 200, performRequest(router).Code)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				name := fmt.Sprintf("page-%d-%d", i, j)
+				r.AddFromString(name, name)
+				r.AddFromString("index", "index")
+				r.Remove(name)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,356 @@
+package multitemplate
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"text/template"
+
+	"github.com/gin-gonic/gin/render"
+)
+
+// defaultTextContentType is used by a TextRender without a content type
+const defaultTextContentType = "text/plain; charset=utf-8"
+
+// TextRender is the text/template counterpart of Render, for output that must
+// not be HTML-escaped such as plain text emails, XML sitemaps or CSV exports.
+// It mirrors the loaders of Renderer and parses every template once.
+type TextRender struct {
+	// ContentType is written with every rendered template,
+	// "text/plain; charset=utf-8" when empty
+	ContentType string
+
+	templates map[string]*template.Template
+}
+
+var _ render.HTMLRender = (*TextRender)(nil)
+
+// NewText creates a TextRender whose templates are rendered with the given content type
+func NewText(contentType string) *TextRender {
+	return &TextRender{
+		ContentType: contentType,
+		templates:   make(map[string]*template.Template),
+	}
+}
+
+// Add new template
+func (r *TextRender) Add(name string, tmpl *template.Template) {
+	if err := r.TryAdd(name, tmpl); err != nil {
+		panic(err)
+	}
+}
+
+// AddFromFiles supply add template from files
+func (r *TextRender) AddFromFiles(name string, files ...string) *template.Template {
+	return mustTextTemplate(r.TryAddFromFiles(name, files...))
+}
+
+// AddFromGlob supply add template from global path
+func (r *TextRender) AddFromGlob(name, glob string) *template.Template {
+	return mustTextTemplate(r.TryAddFromGlob(name, glob))
+}
+
+// AddFromFS supply add template from fs.FS (e.g. embed.FS)
+func (r *TextRender) AddFromFS(name string, fsys fs.FS, files ...string) *template.Template {
+	return mustTextTemplate(r.TryAddFromFS(name, fsys, files...))
+}
+
+// AddFromFSFuncs supply add template from fs.FS (e.g. embed.FS) with callback func
+func (r *TextRender) AddFromFSFuncs(
+	name string,
+	funcMap template.FuncMap,
+	fsys fs.FS,
+	files ...string,
+) *template.Template {
+	return mustTextTemplate(r.TryAddFromFSFuncs(name, funcMap, fsys, files...))
+}
+
+// AddFromString supply add template from strings
+func (r *TextRender) AddFromString(name, templateString string) *template.Template {
+	return mustTextTemplate(r.TryAddFromString(name, templateString))
+}
+
+// AddFromStringsFuncs supply add template from strings
+func (r *TextRender) AddFromStringsFuncs(
+	name string,
+	funcMap template.FuncMap,
+	templateStrings ...string,
+) *template.Template {
+	return mustTextTemplate(r.TryAddFromStringsFuncs(name, funcMap, templateStrings...))
+}
+
+// AddFromStringsFuncsWithOptions supply add template from strings with options
+func (r *TextRender) AddFromStringsFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	templateStrings ...string,
+) *template.Template {
+	return mustTextTemplate(r.TryAddFromStringsFuncsWithOptions(name, funcMap, options, templateStrings...))
+}
+
+// AddFromFilesFuncs supply add template from file callback func
+func (r *TextRender) AddFromFilesFuncs(name string, funcMap template.FuncMap, files ...string) *template.Template {
+	return mustTextTemplate(r.TryAddFromFilesFuncs(name, funcMap, files...))
+}
+
+// AddFromFilesFuncsWithOptions supply add template from file callback func with options
+func (r *TextRender) AddFromFilesFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	files ...string,
+) *template.Template {
+	return mustTextTemplate(r.TryAddFromFilesFuncsWithOptions(name, funcMap, options, files...))
+}
+
+// AddFromGlobFuncsWithOptions supply add template from global path with callback func and options
+func (r *TextRender) AddFromGlobFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	glob string,
+) *template.Template {
+	return mustTextTemplate(r.TryAddFromGlobFuncsWithOptions(name, funcMap, options, glob))
+}
+
+// AddFromFSFuncsWithOptions supply add template from fs.FS (e.g. embed.FS) with callback func and options
+func (r *TextRender) AddFromFSFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	fsys fs.FS,
+	files ...string,
+) *template.Template {
+	return mustTextTemplate(r.TryAddFromFSFuncsWithOptions(name, funcMap, options, fsys, files...))
+}
+
+// TryAdd is like Add but returns an error instead of panicking
+func (r *TextRender) TryAdd(name string, tmpl *template.Template) error {
+	if tmpl == nil {
+		return &TemplateError{Name: name, Err: ErrNilTemplate}
+	}
+	if len(name) == 0 {
+		return &TemplateError{Err: ErrEmptyName}
+	}
+	if _, ok := r.templates[name]; ok {
+		return &TemplateError{Name: name, Err: ErrTemplateExists}
+	}
+	r.templates[name] = tmpl
+	return nil
+}
+
+// TryAddFromFiles is like AddFromFiles but returns an error instead of panicking
+func (r *TextRender) TryAddFromFiles(name string, files ...string) (*template.Template, error) {
+	return r.TryAddFromFilesFuncsWithOptions(name, nil, *NewTemplateOptions(), files...)
+}
+
+// TryAddFromGlob is like AddFromGlob but returns an error instead of panicking
+func (r *TextRender) TryAddFromGlob(name, glob string) (*template.Template, error) {
+	return r.TryAddFromGlobFuncsWithOptions(name, nil, *NewTemplateOptions(), glob)
+}
+
+// TryAddFromFS is like AddFromFS but returns an error instead of panicking
+func (r *TextRender) TryAddFromFS(name string, fsys fs.FS, files ...string) (*template.Template, error) {
+	return r.TryAddFromFSFuncs(name, nil, fsys, files...)
+}
+
+// TryAddFromFSFuncs is like AddFromFSFuncs but returns an error instead of panicking
+func (r *TextRender) TryAddFromFSFuncs(
+	name string,
+	funcMap template.FuncMap,
+	fsys fs.FS,
+	files ...string,
+) (*template.Template, error) {
+	return r.TryAddFromFSFuncsWithOptions(name, funcMap, *NewTemplateOptions(), fsys, files...)
+}
+
+// TryAddFromString is like AddFromString but returns an error instead of panicking
+func (r *TextRender) TryAddFromString(name, templateString string) (*template.Template, error) {
+	return r.TryAddFromStringsFuncs(name, nil, templateString)
+}
+
+// TryAddFromStringsFuncs is like AddFromStringsFuncs but returns an error instead of panicking
+func (r *TextRender) TryAddFromStringsFuncs(
+	name string,
+	funcMap template.FuncMap,
+	templateStrings ...string,
+) (*template.Template, error) {
+	return r.TryAddFromStringsFuncsWithOptions(name, funcMap, *NewTemplateOptions(), templateStrings...)
+}
+
+// TryAddFromStringsFuncsWithOptions is like AddFromStringsFuncsWithOptions
+// but returns an error instead of panicking
+func (r *TextRender) TryAddFromStringsFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	templateStrings ...string,
+) (*template.Template, error) {
+	if err := checkOptions(options.Options); err != nil {
+		return r.add(name, nil, err)
+	}
+	tmpl := newTextTemplate(funcMap, options)(name)
+	for _, ts := range templateStrings {
+		if _, err := tmpl.Parse(ts); err != nil {
+			return r.add(name, nil, newTemplateError("", ts, err))
+		}
+	}
+	return r.add(name, tmpl, nil)
+}
+
+// TryAddFromFilesFuncs is like AddFromFilesFuncs but returns an error instead of panicking
+func (r *TextRender) TryAddFromFilesFuncs(
+	name string,
+	funcMap template.FuncMap,
+	files ...string,
+) (*template.Template, error) {
+	return r.TryAddFromFilesFuncsWithOptions(name, funcMap, *NewTemplateOptions(), files...)
+}
+
+// TryAddFromFilesFuncsWithOptions is like AddFromFilesFuncsWithOptions
+// but returns an error instead of panicking
+func (r *TextRender) TryAddFromFilesFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	files ...string,
+) (*template.Template, error) {
+	if err := checkOptions(options.Options); err != nil {
+		return r.add(name, nil, err)
+	}
+	tmpl, _, err := parseFiles(newTextTemplate(funcMap, options), nil, files)
+	return r.add(name, tmpl, err)
+}
+
+// TryAddFromGlobFuncsWithOptions is like AddFromGlobFuncsWithOptions
+// but returns an error instead of panicking
+func (r *TextRender) TryAddFromGlobFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	glob string,
+) (*template.Template, error) {
+	files, err := globFiles(glob)
+	if err != nil {
+		return r.add(name, nil, err)
+	}
+	return r.TryAddFromFilesFuncsWithOptions(name, funcMap, options, files...)
+}
+
+// TryAddFromFSFuncsWithOptions is like AddFromFSFuncsWithOptions
+// but returns an error instead of panicking
+func (r *TextRender) TryAddFromFSFuncsWithOptions(
+	name string,
+	funcMap template.FuncMap,
+	options TemplateOptions,
+	fsys fs.FS,
+	files ...string,
+) (*template.Template, error) {
+	files, err := globFS(fsys, files)
+	if err != nil {
+		return r.add(name, nil, err)
+	}
+	if err := checkOptions(options.Options); err != nil {
+		return r.add(name, nil, err)
+	}
+	tmpl, _, err := parseFiles(newTextTemplate(funcMap, options), fsys, files)
+	return r.add(name, tmpl, err)
+}
+
+// LoadDirectory registers a template for every page under root, composed with
+// the layouts and partials of its LoadConfig, see Renderer.LoadDirectory.
+func (r *TextRender) LoadDirectory(root string, configs ...LoadConfig) error {
+	return loadDirectory(root, configs, r.TryAddFromFilesFuncsWithOptions)
+}
+
+// LoadFS is like LoadDirectory but reads the templates from fsys (e.g. embed.FS)
+func (r *TextRender) LoadFS(fsys fs.FS, configs ...LoadConfig) error {
+	return loadFS(fsys, configs, r.TryAddFromFSFuncsWithOptions)
+}
+
+// add registers the result of a parse, naming the template in parse errors
+func (r *TextRender) add(name string, tmpl *template.Template, err error) (*template.Template, error) {
+	if err != nil {
+		var te *TemplateError
+		if !errors.As(err, &te) {
+			te = &TemplateError{Err: err}
+		}
+		te.Name = name
+		return nil, te
+	}
+	if err := r.TryAdd(name, tmpl); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// Instance supply render string. A name of the form "page#block" renders only
+// the block of that template. An unknown name renders a 500 error suggesting
+// similar template names.
+func (r *TextRender) Instance(name string, data any) render.Render {
+	name, block := splitBlock(name, r.Has)
+	return r.InstanceBlock(name, block, data)
+}
+
+// InstanceBlock renders only the template defined as block in the template
+// registered under name. An empty block renders the whole template.
+func (r *TextRender) InstanceBlock(name, block string, data any) render.Render {
+	tmpl, ok := r.templates[name]
+	if !ok {
+		return internalError{err: notFoundError(name, r.Names())}
+	}
+	tmpl, err := lookupBlock(tmpl, name, block)
+	if err != nil {
+		return internalError{err: err}
+	}
+	contentType := r.ContentType
+	if contentType == "" {
+		contentType = defaultTextContentType
+	}
+	return textRender{
+		Template:    tmpl,
+		Data:        data,
+		ContentType: contentType,
+	}
+}
+
+// textRender executes a text/template with the configured content type
+type textRender struct {
+	Template    *template.Template
+	Data        any
+	ContentType string
+}
+
+// Render executes the template into w
+func (r textRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return r.Template.Execute(w, r.Data)
+}
+
+// WriteContentType writes the configured content type
+func (r textRender) WriteContentType(w http.ResponseWriter) {
+	header := w.Header()
+	if val := header["Content-Type"]; len(val) == 0 {
+		header["Content-Type"] = []string{r.ContentType}
+	}
+}
+
+// newTextTemplate returns a constructor for root text templates with the
+// given functions and delimiters
+func newTextTemplate(funcMap template.FuncMap, options TemplateOptions) func(string) *template.Template {
+	return func(name string) *template.Template {
+		return template.New(name).
+			Delims(options.LeftDelimiter, options.RightDelimiter).
+			Option(options.Options...).
+			Funcs(funcMap)
+	}
+}
+
+// mustTextTemplate panics if err is not nil, like template.Must
+func mustTextTemplate(tmpl *template.Template, err error) *template.Template {
+	if err != nil {
+		panic(err)
+	}
+	return tmpl
+}
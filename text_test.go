@@ -0,0 +1,103 @@
+package multitemplate
+
+import (
+	"net/http/httptest"
+	"os"
+	"testing"
+	"text/template"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type textRow struct {
+	Name  string
+	Email string
+}
+
+var textRows = []textRow{{Name: "<Tom & Jerry>", Email: "tom@example.com"}}
+
+const textExport = "name,email\n<Tom & Jerry>,tom@example.com\n"
+
+func TestTextRenderLoaders(t *testing.T) {
+	r := NewText("text/csv; charset=utf-8")
+	r.AddFromFiles("files", "tests/text/export.csv", "tests/text/row.csv")
+	r.AddFromGlob("glob", "tests/text/*.csv")
+	r.AddFromFS("fs", os.DirFS("tests/text"), "export.csv", "row.csv")
+	r.AddFromFSFuncs("fsfuncs", template.FuncMap{}, os.DirFS("tests/text"), "export.csv", "row.csv")
+	r.AddFromFilesFuncs("filesfuncs", template.FuncMap{}, "tests/text/export.csv", "tests/text/row.csv")
+	r.AddFromFilesFuncsWithOptions("options", template.FuncMap{}, *NewTemplateOptions(),
+		"tests/text/export.csv", "tests/text/row.csv")
+	r.AddFromStringsFuncs("strings", template.FuncMap{},
+		`name,email`+"\n"+`{{ range . }}{{ template "row" . }}{{ end }}`,
+		`{{ define "row" }}{{ .Name }},{{ .Email }}`+"\n"+`{{ end }}`)
+	r.AddFromStringsFuncsWithOptions("delims", template.FuncMap{}, *NewTemplateOptions(Delims("[[", "]]")),
+		`name,email`+"\n"+`[[ range . ]][[ .Name ]],[[ .Email ]]`+"\n"+`[[ end ]]`)
+	r.Add("tmpl", template.Must(template.New("tmpl").Parse(`{{ range . }}{{ .Name }}{{ end }}`)))
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.GET("/", func(c *gin.Context) {
+		c.HTML(200, c.Query("name"), textRows)
+	})
+
+	for _, name := range []string{"files", "glob", "fs", "fsfuncs", "filesfuncs", "options", "strings", "delims"} {
+		w := performRequestPath(router, "/?name="+name)
+		assert.Equal(t, 200, w.Code, name)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"), name)
+		assert.Equal(t, textExport, w.Body.String(), name)
+	}
+
+	w := performRequestPath(router, "/?name=tmpl")
+	assert.Equal(t, "<Tom & Jerry>", w.Body.String())
+}
+
+func TestTextRenderDefaultContentType(t *testing.T) {
+	r := NewText("")
+	r.AddFromString("hello", "Hello {{ . }}")
+
+	router := gin.New()
+	router.GET("/", func(c *gin.Context) {
+		c.Render(200, r.Instance("hello", "<world>"))
+	})
+
+	w := performRequest(router)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "Hello <world>", w.Body.String())
+}
+
+func TestTextRenderErrors(t *testing.T) {
+	r := NewText("")
+	r.AddFromString("hello", "Hello")
+
+	assert.Panics(t, func() {
+		r.AddFromString("hello", "Hello")
+	})
+	_, err := r.TryAddFromString("hello", "Hello")
+	assert.ErrorIs(t, err, ErrTemplateExists)
+
+	_, err = r.TryAddFromStringsFuncs("broken", nil, "{{ missing }}")
+	var te *TemplateError
+	assert.ErrorAs(t, err, &te)
+	assert.Equal(t, "broken", te.Name)
+	assert.Equal(t, 1, te.Line)
+
+	_, err = r.TryAddFromGlob("none", "tests/text/*.missing")
+	assert.ErrorIs(t, err, ErrNoFiles)
+	_, err = r.TryAddFromFS("none", os.DirFS("tests/text"), "*.missing")
+	assert.ErrorIs(t, err, ErrNoFiles)
+	assert.ErrorIs(t, r.TryAdd("", template.New("x")), ErrEmptyName)
+	assert.ErrorIs(t, r.TryAdd("x", nil), ErrNilTemplate)
+
+	assert.ErrorIs(t, r.Instance("missing", nil).Render(httptest.NewRecorder()), ErrTemplateNotFound)
+}
+
+func TestTextRenderLoadDirectory(t *testing.T) {
+	r := NewText("")
+	assert.NoError(t, r.LoadDirectory("tests/site", siteConfigs...))
+	assert.Len(t, r.templates, 3)
+
+	r = NewText("")
+	assert.NoError(t, r.LoadFS(os.DirFS("tests/site"), siteConfigs...))
+	assert.Len(t, r.templates, 3)
+}
@@ -0,0 +1,291 @@
+package multitemplate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+// ThemeConfig configures a ThemeRender
+type ThemeConfig struct {
+	// Resolve returns the theme of a request, e.g. from its host name. The
+	// Default theme is used when it returns a theme that is not registered.
+	Resolve func(c *gin.Context) string
+	// Default is the theme used for requests without a registered theme
+	Default string
+	// Configs describe the template sets composed for every theme, see LoadFS
+	Configs []LoadConfig
+	// Options configure the renderer of every theme, see NewRenderer
+	Options []Option
+}
+
+// ThemeRender renders the templates of several themes, e.g. white-labelled
+// storefronts. A theme is an ordered list of fs.FS layers, such as tenant
+// theme, base theme and defaults: every file of a template set is read from
+// the first layer that has it, so a theme only holds the templates and
+// partials it overrides. The templates of a theme are composed on first use
+// and cached, as is the error of a theme failing to compose outside of debug
+// mode. The theme of each request is selected by ThemeConfig.Resolve, which
+// needs the Middleware.
+type ThemeRender struct {
+	config ThemeConfig
+
+	mu     sync.RWMutex
+	themes map[string]*theme
+	cache  map[string]composedTheme
+}
+
+// theme is the layers of a registered theme
+type theme struct {
+	layers []fs.FS
+}
+
+// composedTheme is the renderer of a theme, or the error composing it
+type composedTheme struct {
+	renderer Renderer
+	err      error
+}
+
+var _ render.HTMLRender = (*ThemeRender)(nil)
+
+// NewThemeRender creates a ThemeRender without themes, see AddTheme
+func NewThemeRender(config ThemeConfig) *ThemeRender {
+	return &ThemeRender{
+		config: config,
+		themes: make(map[string]*theme),
+		cache:  make(map[string]composedTheme),
+	}
+}
+
+// AddTheme registers the theme name made of layers, the first overriding the
+// others. Registering a theme again replaces it and drops its cached templates.
+func (r *ThemeRender) AddTheme(name string, layers ...fs.FS) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.themes[name] = &theme{layers: layers}
+	delete(r.cache, name)
+}
+
+// Themes returns the registered theme names, sorted
+func (r *ThemeRender) Themes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Sorted(maps.Keys(r.themes))
+}
+
+// Invalidate drops the cached templates or errors of the named themes, or of
+// every theme when no name is given, so that they are composed again on next use
+func (r *ThemeRender) Invalidate(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(names) == 0 {
+		clear(r.cache)
+	}
+	for _, name := range names {
+		delete(r.cache, name)
+	}
+}
+
+// Theme returns the renderer holding the templates of the theme name,
+// composing them if they are not cached. Call it at startup to check that a
+// theme composes, or to render a theme outside of a request with Execute. A
+// theme failing to compose returns the same error until it is registered
+// again or invalidated, except in debug mode where it is composed again on
+// every call until its files are fixed.
+func (r *ThemeRender) Theme(name string) (Renderer, error) {
+	r.mu.RLock()
+	composed, cached := r.cache[name]
+	t, ok := r.themes[name]
+	r.mu.RUnlock()
+	if cached {
+		return composed.renderer, composed.err
+	}
+	if !ok {
+		return nil, fmt.Errorf("theme %q: %w", name, ErrThemeNotFound)
+	}
+
+	composed.renderer = NewRenderer(r.config.Options...)
+	if err := composed.renderer.LoadFS(LayeredFS(t.layers...), r.config.Configs...); err != nil {
+		composed = composedTheme{err: fmt.Errorf("theme %q: %w", name, err)}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if current, ok := r.cache[name]; ok {
+		return current.renderer, current.err
+	}
+	if r.themes[name] == t && (composed.err == nil || !gin.IsDebugging()) {
+		r.cache[name] = composed
+	}
+	return composed.renderer, composed.err
+}
+
+// Middleware returns the middleware giving ThemeConfig.Resolve access to the
+// request
+func (r *ThemeRender) Middleware() gin.HandlerFunc {
+	return nextWithContext
+}
+
+// Instance renders the template name of the theme of the request, or of the
+// default theme. A name of the form "page#block" renders only the block.
+func (r *ThemeRender) Instance(name string, data any) render.Render {
+	return themeRender{r: r, name: name, data: data}
+}
+
+// resolve returns the theme of the request of w
+func (r *ThemeRender) resolve(w http.ResponseWriter) string {
+	c, ok := requestContext(w)
+	if !ok || r.config.Resolve == nil {
+		return r.config.Default
+	}
+	name := r.config.Resolve(c)
+	r.mu.RLock()
+	_, registered := r.themes[name]
+	r.mu.RUnlock()
+	if !registered {
+		return r.config.Default
+	}
+	return name
+}
+
+// instance returns the render.Render of the template in the theme of the request of w
+func (r *ThemeRender) instance(w http.ResponseWriter, name string, data any) render.Render {
+	renderer, err := r.Theme(r.resolve(w))
+	if err != nil {
+		if gin.IsDebugging() {
+			return errorPage{err: err}
+		}
+		return internalError{err: err}
+	}
+	return renderer.Instance(name, data)
+}
+
+// themeRender selects the theme when the response is written
+type themeRender struct {
+	r    *ThemeRender
+	name string
+	data any
+}
+
+// Render writes the template of the theme of the request to w
+func (t themeRender) Render(w http.ResponseWriter) error {
+	return t.r.instance(w, t.name, t.data).Render(w)
+}
+
+// WriteContentType writes the content type of the template
+func (t themeRender) WriteContentType(w http.ResponseWriter) {
+	t.r.instance(w, t.name, t.data).WriteContentType(w)
+}
+
+// LayeredFS returns a read-only fs.FS reading each file from the first of
+// layers that has it. Directories list the entries of every layer.
+func LayeredFS(layers ...fs.FS) fs.FS {
+	return layeredFS(layers)
+}
+
+type layeredFS []fs.FS
+
+// Open opens name in the first layer that has it
+func (l layeredFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	for _, layer := range l {
+		f, err := layer.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if info, err := f.Stat(); err != nil || !info.IsDir() {
+			return f, nil
+		}
+		entries, err := l.ReadDir(name)
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		return &layeredDir{File: f, entries: entries}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// layeredDir is a directory of a layeredFS, listing the entries of every layer
+type layeredDir struct {
+	fs.File // directory of the first layer, for Stat and Close
+	entries []fs.DirEntry
+	offset  int
+}
+
+// ReadDir returns the next n merged entries, or all the remaining ones when n <= 0
+func (d *layeredDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	rest = rest[:min(n, len(rest))]
+	d.offset += len(rest)
+	return rest, nil
+}
+
+// Stat returns the file info of name in the first layer that has it
+func (l layeredFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	for _, layer := range l {
+		info, err := fs.Stat(layer, name)
+		if !errors.Is(err, fs.ErrNotExist) {
+			return info, err
+		}
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir merges the entries of the directory name in every layer, sorted.
+// An entry of a layer hides the entries with the same name of the next ones.
+func (l layeredFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	entries := make(map[string]fs.DirEntry)
+	found := false
+	for _, layer := range l {
+		layerEntries, err := fs.ReadDir(layer, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+		for _, e := range layerEntries {
+			if _, ok := entries[e.Name()]; !ok {
+				entries[e.Name()] = e
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	merged := slices.Collect(maps.Values(entries))
+	slices.SortFunc(merged, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return merged, nil
+}
@@ -0,0 +1,142 @@
+package multitemplate
+
+import (
+	"io/fs"
+	"net/http"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	defaultTheme = fstest.MapFS{
+		"layouts/base.html": {Data: []byte(`<html>{{ template "header" . }}{{ template "content" . }}</html>`)},
+		"partials/header.html": {
+			Data: []byte(`{{ define "header" }}<h1>Shop</h1>{{ end }}`),
+		},
+		"pages/index.html": {Data: []byte(`{{ define "content" }}<p>{{ . }}</p>{{ end }}`)},
+		"pages/about.html": {Data: []byte(`{{ define "content" }}<p>About</p>{{ end }}`)},
+	}
+	baseTheme = fstest.MapFS{
+		"partials/header.html": {Data: []byte(`{{ define "header" }}<h1>Base</h1>{{ end }}`)},
+	}
+	tenantTheme = fstest.MapFS{
+		"pages/index.html": {Data: []byte(`{{ define "content" }}<p>Welcome {{ . }}</p>{{ end }}`)},
+		"pages/sale.html":  {Data: []byte(`{{ define "content" }}<p>Sale</p>{{ end }}`)},
+	}
+)
+
+func TestThemeRender(t *testing.T) {
+	r := NewThemeRender(ThemeConfig{
+		Resolve: func(c *gin.Context) string { return c.GetHeader("X-Tenant") },
+		Default: "default",
+		Configs: []LoadConfig{{Layouts: "layouts/*.html", Partials: "partials/*.html", Pages: "pages"}},
+	})
+	r.AddTheme("default", defaultTheme)
+	r.AddTheme("acme", tenantTheme, baseTheme, defaultTheme)
+	assert.Equal(t, []string{"acme", "default"}, r.Themes())
+
+	router := gin.New()
+	router.HTMLRender = r
+	router.Use(r.Middleware(), Partials("content"))
+	router.GET("/:name", func(c *gin.Context) {
+		c.HTML(http.StatusOK, c.Param("name"), "Ada")
+	})
+
+	for _, tc := range []struct {
+		name     string
+		path     string
+		header   http.Header
+		expected string
+	}{
+		{"default", "/index.html", http.Header{}, "<html><h1>Shop</h1><p>Ada</p></html>"},
+		{"unknown tenant", "/index.html", http.Header{"X-Tenant": {"other"}}, "<html><h1>Shop</h1><p>Ada</p></html>"},
+		{"tenant page and base partial", "/index.html", http.Header{"X-Tenant": {"acme"}},
+			"<html><h1>Base</h1><p>Welcome Ada</p></html>"},
+		{"default page", "/about.html", http.Header{"X-Tenant": {"acme"}}, "<html><h1>Base</h1><p>About</p></html>"},
+		{"tenant only page", "/sale.html", http.Header{"X-Tenant": {"acme"}}, "<html><h1>Base</h1><p>Sale</p></html>"},
+		{"partial", "/index.html", http.Header{"X-Tenant": {"acme"}, "Hx-Request": {"true"}}, "<p>Welcome Ada</p>"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := performRequestHeader(router, tc.path, tc.header)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tc.expected, w.Body.String())
+		})
+	}
+
+	w := performRequestHeader(router, "/sale.html", http.Header{})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestThemeCache(t *testing.T) {
+	r := NewThemeRender(ThemeConfig{
+		Default: "default",
+		Configs: []LoadConfig{{Layouts: "layouts/*.html", Pages: "pages"}},
+	})
+	r.AddTheme("default", defaultTheme)
+
+	first, err := r.Theme("default")
+	assert.NoError(t, err)
+	second, err := r.Theme("default")
+	assert.NoError(t, err)
+	assert.Equal(t, reflect.ValueOf(first).UnsafePointer(), reflect.ValueOf(second).UnsafePointer())
+
+	r.Invalidate()
+	third, err := r.Theme("default")
+	assert.NoError(t, err)
+	assert.NotEqual(t, reflect.ValueOf(first).UnsafePointer(), reflect.ValueOf(third).UnsafePointer())
+
+	_, err = r.Theme("missing")
+	assert.ErrorIs(t, err, ErrThemeNotFound)
+
+	broken := &countingFS{FS: fstest.MapFS{"pages/index.html": {Data: []byte(`{{ end }}`)}}}
+	r.AddTheme("broken", broken, defaultTheme)
+	_, err = r.Theme("broken")
+	assert.ErrorContains(t, err, `theme "broken"`)
+	opens := broken.opens
+	assert.Positive(t, opens)
+	_, err = r.Theme("broken")
+	assert.Error(t, err)
+	assert.Equal(t, 2*opens, broken.opens, "a broken theme is composed again in debug mode")
+
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(gin.DebugMode)
+	r.Invalidate("broken")
+	_, err = r.Theme("broken")
+	assert.Error(t, err)
+	_, again := r.Theme("broken")
+	assert.Equal(t, err, again)
+	assert.Equal(t, 3*opens, broken.opens, "a broken theme is composed only once")
+
+	r.Invalidate("broken")
+	_, err = r.Theme("broken")
+	assert.Error(t, err)
+	assert.Equal(t, 4*opens, broken.opens)
+}
+
+// countingFS counts the files opened in an fs.FS
+type countingFS struct {
+	fs.FS
+	opens int
+}
+
+func (f *countingFS) Open(name string) (fs.File, error) {
+	f.opens++
+	return f.FS.Open(name)
+}
+
+func TestLayeredFS(t *testing.T) {
+	fsys := LayeredFS(tenantTheme, baseTheme, defaultTheme)
+	assert.NoError(t, fstest.TestFS(fsys,
+		"layouts/base.html", "partials/header.html", "pages/index.html", "pages/about.html", "pages/sale.html"))
+
+	b, err := fs.ReadFile(fsys, "pages/index.html")
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "Welcome")
+
+	_, err = fs.Stat(fsys, "pages/missing.html")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
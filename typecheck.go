@@ -0,0 +1,309 @@
+package multitemplate
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"html/template"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template/parse"
+)
+
+// CheckTypes verifies, without executing anything, that the fields and
+// methods each template reads from its data exist in the Go type associated
+// with its name. types maps template names, or "page#block" names, to a value
+// of the data type such as Article{} or (*Article)(nil), or to its
+// reflect.Type. Call it at startup or from a test:
+//
+//	err := multitemplate.CheckTypes(r, map[string]any{
+//		"article.html": ArticleData{},
+//	})
+//
+// Data flowing through with, range, variables and {{ template }} calls is
+// followed. Interfaces, maps and function results are not checked. Every
+// mismatch is a *TemplateError wrapping ErrFieldNotFound; they are returned
+// together.
+func CheckTypes(r Renderer, types map[string]any) error {
+	lookup, ok := r.(blockRenderer)
+	if !ok {
+		return fmt.Errorf("multitemplate: CheckTypes does not support %T", r)
+	}
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(types)) {
+		tmpl, err := lookup.lookup(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		typ, ok := types[name].(reflect.Type)
+		if !ok {
+			typ = reflect.TypeOf(types[name])
+		}
+		c := &typeChecker{name: name, root: tmpl, seen: make(map[typeVisit]bool)}
+		c.checkTemplate(tmpl, dataType{typ: typ})
+		slices.SortStableFunc(c.errs, func(a, b *TemplateError) int {
+			return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+		})
+		for _, err := range c.errs {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// typeVisit is a template checked with a type of dot
+type typeVisit struct {
+	name string
+	typ  dataType
+}
+
+// dataType is the type of a value at a point of a template, nil when unknown.
+// text/template only calls the pointer methods of addressable values: those
+// reached through a pointer or a slice element, but not the data passed by
+// value to Execute, map elements or function results.
+type dataType struct {
+	typ  reflect.Type
+	addr bool
+}
+
+// typeScope is the type of dot and of the variables at a point of a template
+type typeScope struct {
+	dot  dataType
+	vars map[string]dataType
+}
+
+// with returns a copy of the scope with another dot, for the body of a
+// control structure
+func (s typeScope) with(dot dataType) typeScope {
+	return typeScope{dot: dot, vars: maps.Clone(s.vars)}
+}
+
+type typeChecker struct {
+	name string             // registered name of the checked template
+	root *template.Template // template set used for {{ template }} calls
+	tree *parse.Tree        // tree being walked, for error positions
+	seen map[typeVisit]bool
+	errs []*TemplateError
+}
+
+// checkTemplate walks tmpl with dot of type typ, once per type
+func (c *typeChecker) checkTemplate(tmpl *template.Template, typ dataType) {
+	visit := typeVisit{name: tmpl.Name(), typ: typ}
+	if typ.typ == nil || tmpl.Tree == nil || c.seen[visit] {
+		return
+	}
+	c.seen[visit] = true
+
+	tree := c.tree
+	c.tree = tmpl.Tree
+	c.walk(tmpl.Tree.Root, typeScope{dot: typ, vars: map[string]dataType{"$": typ}})
+	c.tree = tree
+}
+
+func (c *typeChecker) walk(node parse.Node, scope typeScope) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			c.walk(child, scope)
+		}
+	case *parse.ActionNode:
+		c.pipe(n.Pipe, scope, true)
+	case *parse.IfNode:
+		inner := scope.with(scope.dot)
+		c.pipe(n.Pipe, inner, true)
+		c.walk(n.List, inner)
+		c.walk(n.ElseList, scope.with(scope.dot))
+	case *parse.WithNode:
+		inner := scope.with(scope.dot)
+		inner.dot = c.pipe(n.Pipe, inner, true)
+		c.walk(n.List, inner)
+		c.walk(n.ElseList, scope.with(scope.dot))
+	case *parse.RangeNode:
+		inner := scope.with(scope.dot)
+		key, elem := rangeTypes(c.pipe(n.Pipe, inner, false))
+		switch len(n.Pipe.Decl) {
+		case 1:
+			inner.vars[n.Pipe.Decl[0].Ident[0]] = elem
+		case 2:
+			inner.vars[n.Pipe.Decl[0].Ident[0]] = key
+			inner.vars[n.Pipe.Decl[1].Ident[0]] = elem
+		}
+		inner.dot = elem
+		c.walk(n.List, inner)
+		c.walk(n.ElseList, scope.with(scope.dot))
+	case *parse.TemplateNode:
+		if n.Pipe == nil {
+			return
+		}
+		if tmpl := c.root.Lookup(n.Name); tmpl != nil {
+			c.checkTemplate(tmpl, c.pipe(n.Pipe, scope, true))
+		}
+	}
+}
+
+// pipe checks the commands of pipe and returns the type of its result. The
+// declared variables are assigned when declare is set.
+func (c *typeChecker) pipe(pipe *parse.PipeNode, scope typeScope, declare bool) dataType {
+	if pipe == nil {
+		return dataType{}
+	}
+	var typ dataType
+	for _, cmd := range pipe.Cmds {
+		typ = c.command(cmd, scope)
+	}
+	if declare {
+		for _, v := range pipe.Decl {
+			scope.vars[v.Ident[0]] = typ
+		}
+	}
+	return typ
+}
+
+// command checks the operands of cmd and returns the type of its result
+func (c *typeChecker) command(cmd *parse.CommandNode, scope typeScope) dataType {
+	for _, arg := range cmd.Args[1:] {
+		c.operand(arg, scope)
+	}
+	return c.operand(cmd.Args[0], scope)
+}
+
+// operand returns the type of node, reporting the fields it fails to resolve
+func (c *typeChecker) operand(node parse.Node, scope typeScope) dataType {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return scope.dot
+	case *parse.FieldNode:
+		return c.fields(n, scope.dot, n.Ident)
+	case *parse.VariableNode:
+		return c.fields(n, scope.vars[n.Ident[0]], n.Ident[1:])
+	case *parse.ChainNode:
+		return c.fields(n, c.operand(n.Node, scope), n.Field)
+	case *parse.PipeNode:
+		return c.pipe(n, scope.with(scope.dot), true)
+	default:
+		return dataType{}
+	}
+}
+
+// fields resolves the chain of field and method names on typ
+func (c *typeChecker) fields(node parse.Node, typ dataType, idents []string) dataType {
+	for _, ident := range idents {
+		var err error
+		typ, err = fieldType(typ, ident)
+		if err != nil {
+			c.report(node, err)
+			return dataType{}
+		}
+	}
+	return typ
+}
+
+func (c *typeChecker) report(node parse.Node, err error) {
+	location, _ := c.tree.ErrorContext(node)
+	te := &TemplateError{Name: c.name, Err: err}
+	parts := strings.Split(location, ":")
+	if len(parts) == 3 {
+		te.File = parts[0]
+		te.Line, _ = strconv.Atoi(parts[1])
+		te.Column, _ = strconv.Atoi(parts[2])
+	}
+	c.errs = append(c.errs, te)
+}
+
+// fieldType returns the type of the field or method name of typ the way
+// text/template resolves it, unknown when it cannot be known statically
+func fieldType(typ dataType, name string) (dataType, error) {
+	if typ.typ == nil {
+		return dataType{}, nil
+	}
+	base, addr := indirectType(typ)
+	methods := methodSet(base, addr)
+	if m, ok := methods.MethodByName(name); ok {
+		if m.Type.NumOut() == 0 {
+			return dataType{}, nil
+		}
+		return dataType{typ: m.Type.Out(0)}, nil
+	}
+
+	switch base.Kind() { //nolint:exhaustive // other kinds have no fields
+	case reflect.Interface:
+		return dataType{}, nil
+	case reflect.Map:
+		if base.Key().Kind() == reflect.String {
+			return dataType{typ: base.Elem()}, nil
+		}
+	case reflect.Struct:
+		if f, ok := base.FieldByName(name); ok && f.IsExported() {
+			return dataType{typ: f.Type, addr: addr}, nil
+		}
+	}
+	return dataType{}, withSuggestions(
+		fmt.Errorf("%w: .%s in type %s", ErrFieldNotFound, name, typ.typ),
+		name, memberNames(base, methods))
+}
+
+// indirectType follows the pointers of typ, whose targets are addressable
+func indirectType(typ dataType) (reflect.Type, bool) {
+	base, addr := typ.typ, typ.addr
+	for base.Kind() == reflect.Pointer {
+		base, addr = base.Elem(), true
+	}
+	return base, addr
+}
+
+// methodSet returns the type whose methods text/template can call on a value
+// of type typ: a pointer to it when the value is addressable
+func methodSet(typ reflect.Type, addr bool) reflect.Type {
+	if addr && typ.Kind() != reflect.Interface {
+		return reflect.PointerTo(typ)
+	}
+	return typ
+}
+
+// memberNames lists the exported fields of typ and the methods of methods
+func memberNames(typ, methods reflect.Type) []string {
+	var names []string
+	if typ.Kind() == reflect.Struct {
+		for _, f := range reflect.VisibleFields(typ) {
+			if f.IsExported() {
+				names = append(names, f.Name)
+			}
+		}
+	}
+	for i := range methods.NumMethod() {
+		names = append(names, methods.Method(i).Name)
+	}
+	return names
+}
+
+// rangeTypes returns the types of the key and element of a range over typ.
+// Slice elements are addressable, map and channel elements are not.
+func rangeTypes(typ dataType) (dataType, dataType) {
+	if typ.typ == nil {
+		return dataType{}, dataType{}
+	}
+	base, addr := indirectType(typ)
+	switch base.Kind() { //nolint:exhaustive // other kinds cannot be ranged over
+	case reflect.Array:
+		return dataType{typ: reflect.TypeFor[int]()}, dataType{typ: base.Elem(), addr: addr}
+	case reflect.Slice:
+		return dataType{typ: reflect.TypeFor[int]()}, dataType{typ: base.Elem(), addr: true}
+	case reflect.Map:
+		return dataType{typ: base.Key()}, dataType{typ: base.Elem()}
+	case reflect.Chan:
+		return dataType{}, dataType{typ: base.Elem()}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return dataType{typ: base}, dataType{typ: base}
+	default:
+		return dataType{}, dataType{}
+	}
+}
@@ -0,0 +1,80 @@
+package multitemplate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typeAuthor struct {
+	Name  string
+	Email string
+}
+
+type typeComment struct {
+	Body string
+}
+
+type typeArticle struct {
+	Title    string
+	Author   *typeAuthor
+	Comments []typeComment
+	Meta     map[string]any
+}
+
+func (a *typeArticle) Summary(n int) string {
+	return a.Title[:n]
+}
+
+func TestCheckTypes(t *testing.T) {
+	r := NewSync()
+	r.AddFromFiles("article.html", "tests/types/article.html")
+
+	err := CheckTypes(r, map[string]any{"article.html": typeArticle{}})
+	assert.ErrorIs(t, err, ErrFieldNotFound)
+
+	var messages []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		messages = append(messages, e.Error())
+	}
+	assert.Equal(t, []string{
+		`multitemplate: template "article.html": article.html:1:7: ` +
+			`field not found: .Tittle in type multitemplate.typeArticle, did you mean "Title"?`,
+		`multitemplate: template "article.html": article.html:2:36: ` +
+			`field not found: .Emial in type *multitemplate.typeAuthor, did you mean "Email"?`,
+		`multitemplate: template "article.html": article.html:3:57: ` +
+			`field not found: .Bdy in type multitemplate.typeComment, did you mean "Body"?`,
+		`multitemplate: template "article.html": article.html:5:3: ` +
+			`field not found: .Summary in type multitemplate.typeArticle`,
+		`multitemplate: template "article.html": article.html:5:76: ` +
+			`field not found: .Missing in type multitemplate.typeArticle`,
+		`multitemplate: template "article.html": article.html:6:36: ` +
+			`field not found: .Age in type *multitemplate.typeAuthor, did you mean "Name"?`,
+	}, messages)
+
+	var te *TemplateError
+	assert.True(t, errors.As(err, &te))
+	assert.Equal(t, "article.html", te.File)
+	assert.Equal(t, 1, te.Line)
+}
+
+func TestCheckTypesOK(t *testing.T) {
+	for name, r := range htmlRenderers() {
+		t.Run(name, func(t *testing.T) {
+			r.AddFromString("ok", `{{ .Title }}{{ .Summary 1 }}{{ range .Comments }}{{ .Body }}{{ else }}{{ .Title }}{{ end }}`)
+			r.AddFromString("summaries", `{{ range . }}{{ .Summary 1 }}{{ end }}`)
+			r.AddFromFiles("users.html", "tests/blocks/users.html")
+
+			assert.NoError(t, CheckTypes(r, map[string]any{
+				"ok":             (*typeArticle)(nil),
+				"summaries":      []typeArticle{},
+				"users.html#row": reflect.TypeFor[string](),
+				"users.html":     []map[string]any{},
+			}))
+			assert.ErrorIs(t, CheckTypes(r, map[string]any{"summaries": map[string]typeArticle{}}), ErrFieldNotFound)
+			assert.ErrorIs(t, CheckTypes(r, map[string]any{"missing": typeArticle{}}), ErrTemplateNotFound)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package multitemplate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// fileStamp records the state of a template source file when it was parsed
+type fileStamp struct {
+	file    string
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+}
+
+// readSource reads a template file from fsys, or from the OS when fsys is nil,
+// returning its content, its template name and a stamp of what was read.
+func readSource(fsys fs.FS, file string) ([]byte, string, fileStamp, error) {
+	stamp := fileStamp{file: file}
+	info, err := statSource(fsys, file)
+	if err != nil {
+		return nil, "", stamp, err
+	}
+
+	var (
+		b    []byte
+		name string
+	)
+	if fsys == nil {
+		b, err = os.ReadFile(file)
+		name = filepath.Base(file)
+	} else {
+		b, err = fs.ReadFile(fsys, file)
+		name = path.Base(file)
+	}
+	if err != nil {
+		return nil, "", stamp, err
+	}
+
+	stamp.modTime = info.ModTime()
+	stamp.size = info.Size()
+	stamp.hash = sha256.Sum256(b)
+	return b, name, stamp, nil
+}
+
+func statSource(fsys fs.FS, file string) (fs.FileInfo, error) {
+	if fsys == nil {
+		return os.Stat(file)
+	}
+	return fs.Stat(fsys, file)
+}
+
+// changed reports whether the file differs from when the stamp was taken.
+// The modification time and size are checked first; the content is only
+// hashed when they differ, and the stamp is refreshed when the content
+// turns out to be the same.
+func (s *fileStamp) changed(fsys fs.FS) bool {
+	info, err := statSource(fsys, s.file)
+	if err != nil {
+		return true
+	}
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return false
+	}
+
+	_, _, stamp, err := readSource(fsys, s.file)
+	if err != nil || !bytes.Equal(stamp.hash[:], s.hash[:]) {
+		return true
+	}
+	*s = stamp
+	return false
+}